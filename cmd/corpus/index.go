@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+func runIndex(args []string) error {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	corpus := fs.String("corpus", corpusRootPath(), "root of the corpus")
+	_ = fs.Parse(args)
+
+	idx, err := buildIndex(*corpus)
+	if err != nil {
+		return err
+	}
+
+	out := filepath.Join(*corpus, schema.IndexFile)
+	if err := writeIndex(out, idx); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "indexed %d vectors into %s\n", len(idx.Vectors), out)
+	return nil
+}
+
+// buildIndex walks the corpus rooted at the supplied directory, and indexes
+// every vector in it, in path order.
+func buildIndex(root string) (*schema.Index, error) {
+	files, err := schema.VectorFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := new(schema.Index)
+	for _, p := range files {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vector %s: %w", p, err)
+		}
+		var vector schema.TestVector
+		if err := json.Unmarshal(raw, &vector); err != nil {
+			return nil, fmt.Errorf("failed to parse vector %s: %w", p, err)
+		}
+
+		entry, err := schema.NewIndexEntry(filepath.ToSlash(rel), &vector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index vector %s: %w", p, err)
+		}
+		idx.Vectors = append(idx.Vectors, entry)
+	}
+
+	sort.Slice(idx.Vectors, func(i, j int) bool { return idx.Vectors[i].Path < idx.Vectors[j].Path })
+	return idx, nil
+}
+
+func writeIndex(file string, idx *schema.Index) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(idx); err != nil {
+		return fmt.Errorf("failed to serialize index: %w", err)
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0644)
+}
+
+func loadIndex(file string) (*schema.Index, error) {
+	raw, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("index %s does not exist; build it with the index subcommand", file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index %s: %w", file, err)
+	}
+	var idx schema.Index
+	if err := json.Unmarshal(raw, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %w", file, err)
+	}
+	return &idx, nil
+}
@@ -0,0 +1,96 @@
+// Command corpus builds an index of the test vector corpus, and queries it.
+//
+// Usage:
+//
+//  go run ./cmd/corpus index [flags]
+//  go run ./cmd/corpus query [flags]
+//
+// The index subcommand walks the corpus, and writes an index with an entry
+// per vector to index.json at its root (see schema.Index). Every entry
+// carries the path, class, ID, version, variants, selector, hints, tags,
+// message count and content hash of the vector. Drivers can use the index to
+// plan runs and select subsets of vectors without parsing every vector.
+//
+// The query subcommand prints the vectors in the index that match all the
+// supplied criteria. For example, to list all tipset-class vectors requiring
+// the chaos actor that run at network version 4:
+//
+//  go run ./cmd/corpus query -class tipset -selector chaos_actor=true -nv 4
+//
+// Flags supported by both subcommands:
+//
+//  -corpus <dir>
+//		root of the corpus; defaults to the corpus/ directory of this repo.
+//
+// Flags supported by the query subcommand:
+//
+//  -class <class>
+//		class of the vectors: message, tipset or blockseq.
+//
+//  -id <regex>
+//		regex matched against the vector IDs.
+//
+//  -selector <key=value>
+//		selector the vectors must carry; can be repeated.
+//
+//  -tag <tag>
+//		tag the vectors must carry; can be repeated.
+//
+//  -hint <hint>
+//		hint the vectors must carry; can be repeated.
+//
+//  -exclude-hint <hint>
+//		hint the vectors must not carry; can be repeated.
+//
+//  -variant <id>
+//		variant the vectors must have.
+//
+//  -nv <network version>
+//		network version at which the vectors must have a variant.
+//
+//  -format <paths|json>
+//		output format; paths prints one vector path per line, relative to the
+//		corpus root, and json prints the matching index entries. Defaults to
+//		paths.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "index":
+		err = runIndex(args)
+	case "query":
+		err = runQuery(args)
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <index|query> [flags]\n", os.Args[0])
+	os.Exit(2)
+}
+
+func rootPath() string {
+	_, filename, _, _ := runtime.Caller(0)
+	return path.Dir(path.Dir(filename))
+}
+
+func corpusRootPath() string {
+	return path.Join(rootPath(), "../corpus")
+}
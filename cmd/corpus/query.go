@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// stringsFlag is a flag that can be repeated, accumulating its values.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func runQuery(args []string) error {
+	var (
+		fs = flag.NewFlagSet("query", flag.ExitOnError)
+
+		corpus       = fs.String("corpus", corpusRootPath(), "root of the corpus")
+		class        = fs.String("class", "", "class of the vectors: message, tipset or blockseq")
+		id           = fs.String("id", "", "regex matched against the vector IDs")
+		variant      = fs.String("variant", "", "variant the vectors must have")
+		nv           = fs.Int("nv", -1, "network version at which the vectors must have a variant")
+		format       = fs.String("format", "paths", "output format: paths or json")
+		selectors    stringsFlag
+		tags         stringsFlag
+		hints        stringsFlag
+		excludeHints stringsFlag
+	)
+	fs.Var(&selectors, "selector", "selector the vectors must carry, as key=value; can be repeated")
+	fs.Var(&tags, "tag", "tag the vectors must carry; can be repeated")
+	fs.Var(&hints, "hint", "hint the vectors must carry; can be repeated")
+	fs.Var(&excludeHints, "exclude-hint", "hint the vectors must not carry; can be repeated")
+	_ = fs.Parse(args)
+
+	q := schema.Query{
+		Class:        schema.Class(*class),
+		Tags:         tags,
+		Hints:        hints,
+		ExcludeHints: excludeHints,
+		Variant:      *variant,
+	}
+	switch q.Class {
+	case "", schema.ClassMessage, schema.ClassTipset, schema.ClassBlockSeq:
+	default:
+		return fmt.Errorf("unknown class: %s", q.Class)
+	}
+	if *id != "" {
+		exp, err := regexp.Compile(*id)
+		if err != nil {
+			return fmt.Errorf("supplied id regex %s is invalid: %w", *id, err)
+		}
+		q.ID = exp
+	}
+	if *nv >= 0 {
+		v := uint(*nv)
+		q.NetworkVersion = &v
+	}
+	for _, s := range selectors {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("supplied selector %s is invalid; expected key=value", s)
+		}
+		if q.Selector == nil {
+			q.Selector = make(schema.Selector)
+		}
+		q.Selector[kv[0]] = kv[1]
+	}
+
+	idx, err := loadIndex(filepath.Join(*corpus, schema.IndexFile))
+	if err != nil {
+		return err
+	}
+
+	matches := idx.Query(q)
+	if matches == nil {
+		matches = []schema.IndexEntry{}
+	}
+	switch *format {
+	case "paths":
+		for _, e := range matches {
+			fmt.Println(e.Path)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(schema.Index{Vectors: matches}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output format: %s", *format)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d of %d vectors matched\n", len(matches), len(idx.Vectors))
+	return nil
+}
@@ -0,0 +1,119 @@
+// Command run executes test vectors against the Lotus conformance driver, and
+// reports the outcome of every vector variant.
+//
+// Usage:
+//
+//  go run ./cmd/run [flags] [file or directory...]
+//
+// If no paths are supplied, the corpus/ directory of this repo is used.
+//
+// Supported flags:
+//
+//  -format <human|json|junit>
+//		output format of the report; defaults to human.
+//
+//  -out <file>
+//		file to write the report to; if omitted, the report is written to
+//		stdout.
+//
+//  -incorrect
+//		run vectors carrying the "incorrect" hint, instead of skipping them.
+//		If they also carry the "negate" hint, the postcondition checks are
+//		negated.
+//
+//  -p <n>
+//		number of vectors to run in parallel; defaults to the number of CPUs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"runtime"
+	"sync"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+func main() {
+	var (
+		format    string
+		out       string
+		incorrect bool
+		parallel  int
+	)
+	flag.StringVar(&format, "format", "human", "output format of the report: human, json or junit")
+	flag.StringVar(&out, "out", "", "file to write the report to; if omitted, the report is written to stdout")
+	flag.BoolVar(&incorrect, "incorrect", false, "run vectors carrying the 'incorrect' hint instead of skipping them, negating postconditions if they carry the 'negate' hint")
+	flag.IntVar(&parallel, "p", runtime.NumCPU(), "number of vectors to run in parallel")
+	flag.Parse()
+
+	reporter, ok := reporters[format]
+	if !ok {
+		log.Fatalf("unknown report format: %s", format)
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{corpusRootPath()}
+	}
+
+	files, err := schema.VectorFiles(paths...)
+	if err != nil {
+		log.Fatalf("failed to enumerate vectors: %s", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([][]Result, len(files))
+		sem     = make(chan struct{}, parallel)
+		opts    = Options{RunIncorrect: incorrect}
+	)
+	for i, file := range files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, file string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = RunFile(file, opts)
+		}(i, file)
+	}
+	wg.Wait()
+
+	var all []Result
+	for _, rs := range results {
+		all = append(all, rs...)
+	}
+
+	w := os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("failed to create report file %s: %s", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := reporter(w, all); err != nil {
+		log.Fatalf("failed to write report: %s", err)
+	}
+
+	if s := summarize(all); s.Fail > 0 {
+		// flush the report file before exiting.
+		_ = w.Sync()
+		fmt.Fprintf(os.Stderr, "%d vector variants failed\n", s.Fail)
+		os.Exit(1)
+	}
+}
+
+func rootPath() string {
+	_, filename, _, _ := runtime.Caller(0)
+	return path.Dir(path.Dir(filename))
+}
+
+func corpusRootPath() string {
+	return path.Join(rootPath(), "../corpus")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/crypto"
+
+	"github.com/chenjianmei111/lotus/chain/vm"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// replayingRand is a vm.Rand that serves the randomness recorded in a vector,
+// falling back to schema.DefaultRandomness for unmatched requests.
+type replayingRand struct {
+	recorded schema.Randomness
+}
+
+var _ vm.Rand = (*replayingRand)(nil)
+
+func newReplayingRand(recorded schema.Randomness) *replayingRand {
+	return &replayingRand{recorded: recorded}
+}
+
+func (r *replayingRand) GetChainRandomness(_ context.Context, pers crypto.DomainSeparationTag, round abi.ChainEpoch, entropy []byte) ([]byte, error) {
+	return r.match(schema.RandomnessChain, pers, round, entropy), nil
+}
+
+func (r *replayingRand) GetBeaconRandomness(_ context.Context, pers crypto.DomainSeparationTag, round abi.ChainEpoch, entropy []byte) ([]byte, error) {
+	return r.match(schema.RandomnessBeacon, pers, round, entropy), nil
+}
+
+func (r *replayingRand) match(kind schema.RandomnessKind, pers crypto.DomainSeparationTag, round abi.ChainEpoch, entropy []byte) []byte {
+	ret, ok := r.recorded.Match(schema.RandomnessRule{
+		Kind:                kind,
+		DomainSeparationTag: int64(pers),
+		Epoch:               int64(round),
+		Entropy:             entropy,
+	})
+	if !ok {
+		return []byte(schema.DefaultRandomness)
+	}
+	return ret
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// Reporter writes the results of a run in a particular format.
+type Reporter func(w io.Writer, results []Result) error
+
+// reporters enumerates the supported report formats.
+var reporters = map[string]Reporter{
+	"human": reportHuman,
+	"json":  reportJSON,
+	"junit": reportJUnit,
+}
+
+// Summary tallies the results of a run.
+type Summary struct {
+	Total int `json:"total"`
+	Pass  int `json:"pass"`
+	Fail  int `json:"fail"`
+	Skip  int `json:"skip"`
+}
+
+func summarize(results []Result) Summary {
+	s := Summary{Total: len(results)}
+	for _, r := range results {
+		switch r.Status {
+		case StatusPass:
+			s.Pass++
+		case StatusFail:
+			s.Fail++
+		case StatusSkip:
+			s.Skip++
+		}
+	}
+	return s
+}
+
+func reportHuman(w io.Writer, results []Result) error {
+	icons := map[Status]string{
+		StatusPass: "✅",
+		StatusFail: "❌",
+		StatusSkip: "⏩",
+	}
+	for _, r := range results {
+		line := fmt.Sprintf("%s %s (%s) [%s]", icons[r.Status], r.File, r.ID, r.Variant)
+		if r.Reason != "" {
+			line += ": " + r.Reason
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	s := summarize(results)
+	_, err := fmt.Fprintf(w, "\ntotal: %d, pass: %d, fail: %d, skip: %d\n", s.Total, s.Pass, s.Fail, s.Skip)
+	return err
+}
+
+func reportJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(struct {
+		Summary Summary  `json:"summary"`
+		Results []Result `json:"results"`
+	}{summarize(results), results})
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// reportJUnit writes a JUnit XML report, with one test suite per vector
+// directory, and one test case per vector variant.
+func reportJUnit(w io.Writer, results []Result) error {
+	bydir := make(map[string]*junitTestSuite)
+	for _, r := range results {
+		dir := filepath.Dir(r.File)
+		suite, ok := bydir[dir]
+		if !ok {
+			suite = &junitTestSuite{Name: dir}
+			bydir[dir] = suite
+		}
+
+		tc := junitTestCase{
+			Classname: dir,
+			Name:      fmt.Sprintf("%s/%s", filepath.Base(r.File), r.Variant),
+			Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+		}
+		switch r.Status {
+		case StatusFail:
+			tc.Failure = &junitMessage{Message: r.Reason}
+			suite.Failures++
+		case StatusSkip:
+			tc.Skipped = &junitMessage{Message: r.Reason}
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	dirs := make([]string, 0, len(bydir))
+	for dir := range bydir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var out junitTestSuites
+	for _, dir := range dirs {
+		suite := bydir[dir]
+		var total float64
+		for _, r := range results {
+			if filepath.Dir(r.File) == dir {
+				total += r.Duration.Seconds()
+			}
+		}
+		suite.Time = fmt.Sprintf("%.3f", total)
+		out.Suites = append(out.Suites, *suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
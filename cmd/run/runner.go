@@ -0,0 +1,492 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/chain/vm"
+	"github.com/chenjianmei111/lotus/conformance"
+	"github.com/chenjianmei111/lotus/lib/blockstore"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipld/go-car"
+
+	"github.com/chenjianmei111/test-vectors/gen/builders"
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// Status is the outcome of running a vector variant.
+type Status string
+
+const (
+	StatusPass = Status("pass")
+	StatusFail = Status("fail")
+	StatusSkip = Status("skip")
+)
+
+// Result is the outcome of running a single variant of a vector.
+type Result struct {
+	File     string        `json:"file"`
+	ID       string        `json:"id"`
+	Class    schema.Class  `json:"class"`
+	Variant  string        `json:"variant,omitempty"`
+	Status   Status        `json:"status"`
+	Reason   string        `json:"reason,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Options tunes how vectors are run.
+type Options struct {
+	// RunIncorrect runs vectors carrying the schema.HintIncorrect hint,
+	// instead of skipping them.
+	RunIncorrect bool
+}
+
+// knownProtocolVersions are the protocol version codenames supported by the
+// driver, usable as values of the schema.SelectorMinProtocolVersion selector.
+// They are those vectors are generated against.
+var knownProtocolVersions = func() map[string]struct{} {
+	ret := make(map[string]struct{}, len(builders.KnownProtocolVersions))
+	for _, pv := range builders.KnownProtocolVersions {
+		ret[pv.ID] = struct{}{}
+	}
+	return ret
+}()
+
+// supportedSelectors maps the selectors understood by this runner to a
+// function that determines whether the selector value is satisfied.
+var supportedSelectors = map[string]func(value string) bool{
+	// the Lotus driver provisions the chaos actor when the selector is
+	// present with value "true".
+	schema.SelectorChaosActor: func(string) bool { return true },
+	// message signatures are verified by this runner.
+	schema.SelectorSignedMessages: func(string) bool { return true },
+	schema.SelectorMinProtocolVersion: func(value string) bool {
+		_, ok := knownProtocolVersions[value]
+		return ok
+	},
+}
+
+// RunFile loads the vector stored in the supplied file, and runs all of its
+// variants.
+func RunFile(file string, opts Options) []Result {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return []Result{{File: file, Status: StatusFail, Reason: fmt.Sprintf("failed to read vector: %s", err)}}
+	}
+
+	var vector schema.TestVector
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return []Result{{File: file, Status: StatusFail, Reason: fmt.Sprintf("failed to parse vector: %s", err)}}
+	}
+
+	var id string
+	if vector.Meta != nil {
+		id = vector.Meta.ID
+	}
+
+	base := Result{File: file, ID: id, Class: vector.Class}
+	if vector.Pre == nil || len(vector.Pre.Variants) == 0 {
+		base.Status, base.Reason = StatusFail, "vector has no variants"
+		return []Result{base}
+	}
+
+	results := make([]Result, 0, len(vector.Pre.Variants))
+	for _, variant := range vector.Pre.Variants {
+		r := base
+		r.Variant = variant.ID
+
+		if reason, skip := shouldSkip(&vector, opts); skip {
+			r.Status, r.Reason = StatusSkip, reason
+			results = append(results, r)
+			continue
+		}
+
+		start := time.Now()
+		r.Status, r.Reason = runVariant(&vector, variant)
+		r.Duration = time.Since(start)
+		results = append(results, r)
+	}
+	return results
+}
+
+// shouldSkip determines if the vector must be skipped, due to unsupported
+// selectors or hints.
+func shouldSkip(vector *schema.TestVector, opts Options) (reason string, skip bool) {
+	for k, v := range vector.Selector {
+		satisfied, ok := supportedSelectors[k]
+		if !ok {
+			return fmt.Sprintf("unsupported selector: %s", k), true
+		}
+		if !satisfied(v) {
+			return fmt.Sprintf("unsatisfied selector: %s=%s", k, v), true
+		}
+	}
+	if hasHint(vector, schema.HintIncorrect) && !opts.RunIncorrect {
+		return "vector is knowingly incorrect", true
+	}
+	return "", false
+}
+
+func hasHint(vector *schema.TestVector, hint string) bool {
+	for _, h := range vector.Hints {
+		if h == hint {
+			return true
+		}
+	}
+	return false
+}
+
+// runVariant runs a single variant of the vector, and returns its status,
+// negating the postcondition checks if the vector carries the
+// schema.HintNegate hint.
+func runVariant(vector *schema.TestVector, variant schema.Variant) (Status, string) {
+	var (
+		mismatches []string
+		err        error
+	)
+
+	switch vector.Class {
+	case schema.ClassMessage:
+		mismatches, err = executeMessageVector(vector, variant)
+	case schema.ClassTipset:
+		mismatches, err = executeTipsets(vector, variant)
+	case schema.ClassBlockSeq:
+		mismatches, err = executeBlockSeq(vector, variant)
+	default:
+		return StatusSkip, fmt.Sprintf("unsupported vector class: %s", vector.Class)
+	}
+
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+
+	if hasHint(vector, schema.HintNegate) {
+		if len(mismatches) == 0 {
+			return StatusFail, "postconditions matched, but the vector is negated"
+		}
+		return StatusPass, fmt.Sprintf("negated: %s", strings.Join(mismatches, "; "))
+	}
+
+	if len(mismatches) > 0 {
+		return StatusFail, strings.Join(mismatches, "; ")
+	}
+	return StatusPass, ""
+}
+
+// loadStores creates a fresh in-memory blockstore and datastore, and loads
+// the vector's CAR into them.
+func loadStores(vector *schema.TestVector) (blockstore.Blockstore, ds.Batching, error) {
+	var (
+		mds = ds.NewMapDatastore()
+		bs  = blockstore.NewBlockstore(mds)
+	)
+
+	gr, err := gzip.NewReader(bytes.NewReader(vector.CAR))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to inflate CAR: %w", err)
+	}
+	defer gr.Close()
+
+	if _, err := car.LoadCar(bs, gr); err != nil {
+		return nil, nil, fmt.Errorf("failed to load CAR: %w", err)
+	}
+	return bs, mds, nil
+}
+
+// executeMessageVector applies the messages of a message-class vector, and
+// returns the postcondition mismatches.
+func executeMessageVector(vector *schema.TestVector, variant schema.Variant) ([]string, error) {
+	bs, _, err := loadStores(vector)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		driver     = conformance.NewDriver(context.Background(), vector.Selector, conformance.DriverOpts{})
+		root       = vector.Pre.StateTree.RootCID
+		rand       = newReplayingRand(vector.Randomness)
+		failures   = make(map[int]struct{}, len(vector.Post.ApplyMessageFailures))
+		mismatches []string
+	)
+
+	for _, idx := range vector.Post.ApplyMessageFailures {
+		failures[idx] = struct{}{}
+	}
+
+	for i, m := range vector.ApplyMessages {
+		msg, smsg, err := decodeMessage(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize message %d: %w", i, err)
+		}
+
+		epoch := variant.Epoch
+		if m.EpochOffset != nil {
+			epoch += *m.EpochOffset
+		}
+
+		var (
+			ret      *vm.ApplyRet
+			postRoot cid.Cid
+		)
+		// messages with invalid signatures are rejected before execution.
+		if smsg != nil {
+			err = builders.VerifyMessageSignature(bs, root, smsg)
+		}
+		if err == nil {
+			ret, postRoot, err = driver.ExecuteMessage(bs, conformance.ExecuteMessageParams{
+				Preroot:    root,
+				Epoch:      abi.ChainEpoch(epoch),
+				Message:    msg,
+				BaseFee:    conformance.BaseFeeOrDefault(vector.Pre.BaseFee),
+				CircSupply: conformance.CircSupplyOrDefault(vector.Pre.CircSupply),
+				Rand:       rand,
+			})
+		}
+
+		_, expectFailure := failures[i]
+		switch {
+		case err != nil && !expectFailure:
+			mismatches = append(mismatches, fmt.Sprintf("message %d failed to apply: %s", i, err))
+			continue
+		case err != nil:
+			continue
+		case expectFailure:
+			mismatches = append(mismatches, fmt.Sprintf("message %d was applied, but was expected to fail", i))
+		}
+
+		root = postRoot
+
+		if i >= len(vector.Post.Receipts) {
+			mismatches = append(mismatches, fmt.Sprintf("no receipt for message %d", i))
+			continue
+		}
+		mismatches = append(mismatches, compareReceipt(i, vector.Post.Receipts[i], ret)...)
+	}
+
+	if expected := vector.Post.StateTree.RootCID; root != expected {
+		mismatches = append(mismatches, fmt.Sprintf("wrong post root cid; expected %s, got %s", expected, root))
+	}
+	return mismatches, nil
+}
+
+// decodeMessage decodes a message to apply, returning the bare message, and
+// the signed message if it's signed.
+func decodeMessage(m schema.Message) (*types.Message, *types.SignedMessage, error) {
+	if !m.Signed {
+		msg, err := types.DecodeMessage(m.Bytes)
+		return msg, nil, err
+	}
+	smsg, err := types.DecodeSignedMessage(m.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &smsg.Message, smsg, nil
+}
+
+// executeTipsets applies the tipsets of a tipset-class vector on top of its
+// precondition state, and returns the postcondition mismatches.
+func executeTipsets(vector *schema.TestVector, variant schema.Variant) ([]string, error) {
+	r, err := newTipsetRun(vector, variant)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vector.ApplyTipsets {
+		if _, err := r.apply(vector.ApplyTipsets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return r.finish(), nil
+}
+
+// executeBlockSeq validates the blocks of a blockseq-class vector in arrival
+// order, checking that every block reaches the expected outcome, and applies
+// the blocks found valid at every epoch as a tipset. It returns the outcome
+// and postcondition mismatches.
+func executeBlockSeq(vector *schema.TestVector, variant schema.Variant) ([]string, error) {
+	r, err := newTipsetRun(vector, variant)
+	if err != nil {
+		return nil, err
+	}
+	head, err := builders.NewBlockSeqHead(r.bs, r.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mismatches []string
+		blocks     = vector.ApplyBlocks
+	)
+	for start := 0; start < len(blocks); {
+		var (
+			offset   = blocks[start].EpochOffset
+			epoch    = abi.ChainEpoch(variant.Epoch + offset)
+			ts       = schema.Tipset{EpochOffset: offset}
+			accepted []cid.Cid
+			i        = start
+		)
+		// blocks arriving at the same epoch are validated against the same
+		// head, and the valid ones form the tipset of that epoch.
+		for ; i < len(blocks) && blocks[i].EpochOffset == offset; i++ {
+			ab := blocks[i]
+			header, err := head.ValidateBlock(r.bs, epoch, ab)
+			switch {
+			case err != nil && ab.Expect == schema.BlockAccept:
+				mismatches = append(mismatches, fmt.Sprintf("block %d was rejected, but was expected to be accepted: %s", i, err))
+				continue
+			case err != nil:
+				continue
+			case ab.Expect != schema.BlockAccept:
+				mismatches = append(mismatches, fmt.Sprintf("block %d was accepted, but was expected to be rejected: %s", i, ab.Reason))
+			}
+
+			var winCount int64
+			if header.ElectionProof != nil {
+				winCount = header.ElectionProof.WinCount
+			}
+			ts.BaseFee = ab.Header.ParentBaseFee
+			ts.Blocks = append(ts.Blocks, schema.Block{
+				MinerAddr:    header.Miner,
+				WinCount:     winCount,
+				BLSMessages:  ab.BLSMessages,
+				SECPMessages: ab.SECPMessages,
+				BLSAggregate: ab.Header.BLSAggregate,
+			})
+			accepted = append(accepted, header.Cid())
+		}
+		start = i
+
+		if len(accepted) == 0 {
+			// all blocks at this epoch were rejected; this is a null round.
+			continue
+		}
+
+		ret, err := r.apply(ts)
+		if err != nil {
+			return nil, err
+		}
+		head = builders.BlockSeqHead{Parents: accepted, StateRoot: ret.PostStateRoot, Receipts: ret.ReceiptsRoot}
+	}
+	return append(mismatches, r.finish()...), nil
+}
+
+// tipsetRun applies tipsets in sequence on top of the precondition state of a
+// vector, comparing the results against its postconditions.
+type tipsetRun struct {
+	vector  *schema.TestVector
+	variant schema.Variant
+
+	bs     blockstore.Blockstore
+	mds    ds.Batching
+	driver *conformance.Driver
+	rand   *replayingRand
+
+	root       cid.Cid
+	prevEpoch  int64
+	applied    int
+	receiptIdx int
+	mismatches []string
+}
+
+func newTipsetRun(vector *schema.TestVector, variant schema.Variant) (*tipsetRun, error) {
+	bs, mds, err := loadStores(vector)
+	if err != nil {
+		return nil, err
+	}
+	return &tipsetRun{
+		vector:    vector,
+		variant:   variant,
+		bs:        bs,
+		mds:       mds,
+		driver:    conformance.NewDriver(context.Background(), vector.Selector, conformance.DriverOpts{}),
+		rand:      newReplayingRand(vector.Randomness),
+		root:      vector.Pre.StateTree.RootCID,
+		prevEpoch: variant.Epoch,
+	}, nil
+}
+
+// apply applies the next tipset, and compares its receipts and receipts root
+// against the postconditions.
+func (r *tipsetRun) apply(ts schema.Tipset) (*conformance.ExecuteTipsetResult, error) {
+	var (
+		i         = r.applied
+		execEpoch = r.variant.Epoch + ts.EpochOffset
+	)
+
+	// signed blocks with invalid signatures are discarded.
+	exec, err := builders.ExecutableTipset(r.bs, r.root, &ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the signatures of tipset %d: %w", i, err)
+	}
+
+	ret, err := r.driver.ExecuteTipset(r.bs, r.mds, conformance.ExecuteTipsetParams{
+		Preroot:     r.root,
+		ParentEpoch: abi.ChainEpoch(r.prevEpoch),
+		Tipset:      exec,
+		ExecEpoch:   abi.ChainEpoch(execEpoch),
+		Rand:        r.rand,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply tipset %d: %w", i, err)
+	}
+
+	for _, res := range ret.AppliedResults {
+		if r.receiptIdx >= len(r.vector.Post.Receipts) {
+			r.mismatches = append(r.mismatches, fmt.Sprintf("no receipt for message %d", r.receiptIdx))
+		} else {
+			r.mismatches = append(r.mismatches, compareReceipt(r.receiptIdx, r.vector.Post.Receipts[r.receiptIdx], res)...)
+		}
+		r.receiptIdx++
+	}
+
+	if i >= len(r.vector.Post.ReceiptsRoots) {
+		r.mismatches = append(r.mismatches, fmt.Sprintf("no receipts root for tipset %d", i))
+	} else if expected := r.vector.Post.ReceiptsRoots[i]; ret.ReceiptsRoot != expected {
+		r.mismatches = append(r.mismatches, fmt.Sprintf("wrong receipts root for tipset %d; expected %s, got %s", i, expected, ret.ReceiptsRoot))
+	}
+
+	r.root = ret.PostStateRoot
+	r.prevEpoch = execEpoch
+	r.applied++
+	return ret, nil
+}
+
+// finish compares the final state against the postconditions, and returns
+// all mismatches found.
+func (r *tipsetRun) finish() []string {
+	if r.receiptIdx != len(r.vector.Post.Receipts) {
+		r.mismatches = append(r.mismatches, fmt.Sprintf("expected %d receipts, got %d", len(r.vector.Post.Receipts), r.receiptIdx))
+	}
+	if expected := r.vector.Post.StateTree.RootCID; r.root != expected {
+		r.mismatches = append(r.mismatches, fmt.Sprintf("wrong post root cid; expected %s, got %s", expected, r.root))
+	}
+	return r.mismatches
+}
+
+// compareReceipt compares the expected receipt of the message at the
+// supplied index against the actual one.
+func compareReceipt(idx int, expected *schema.Receipt, actual *vm.ApplyRet) []string {
+	if expected == nil {
+		return []string{fmt.Sprintf("message %d was applied, but no receipt was expected", idx)}
+	}
+
+	var mismatches []string
+	if exp, act := expected.ExitCode, int64(actual.ExitCode); exp != act {
+		mismatches = append(mismatches, fmt.Sprintf("wrong exit code for message %d; expected %d, got %d", idx, exp, act))
+	}
+	if exp, act := []byte(expected.ReturnValue), actual.Return; !bytes.Equal(exp, act) {
+		mismatches = append(mismatches, fmt.Sprintf("wrong return value for message %d; expected %x, got %x", idx, exp, act))
+	}
+	if exp, act := expected.GasUsed, actual.GasUsed; exp != act {
+		mismatches = append(mismatches, fmt.Sprintf("wrong gas used for message %d; expected %d, got %d", idx, exp, act))
+	}
+	return mismatches
+}
@@ -0,0 +1,115 @@
+// Command traceview prints the execution traces embedded in the diagnostics of
+// test vectors, as call trees, one per applied message.
+//
+// Usage:
+//
+//  go run ./cmd/traceview [flags] <vector.json...>
+//
+// Every call is printed with its sender, receiver, method, value, exit code
+// and gas used.
+//
+// Supported flags:
+//
+//  -gas
+//		also print the gas charges incurred by every call.
+//
+//  -m <n>
+//		only print the trace of the n-th applied message (0-indexed).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+func main() {
+	var (
+		gas bool
+		msg int
+	)
+	flag.BoolVar(&gas, "gas", false, "also print the gas charges incurred by every call")
+	flag.IntVar(&msg, "m", -1, "only print the trace of the n-th applied message (0-indexed)")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] <vector.json...>\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	for _, file := range flag.Args() {
+		traces, err := loadTraces(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("=== %s\n", file)
+		if len(traces) == 0 {
+			fmt.Println("no execution traces")
+			continue
+		}
+		for i, t := range traces {
+			if msg >= 0 && i != msg {
+				continue
+			}
+			fmt.Printf("\nmessage %d:\n", i)
+			printTrace(os.Stdout, t, "", gas)
+		}
+	}
+}
+
+func loadTraces(file string) ([]schema.ExecutionTrace, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vector %s: %w", file, err)
+	}
+	var vector schema.TestVector
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return nil, fmt.Errorf("failed to parse vector %s: %w", file, err)
+	}
+	if vector.Diagnostics == nil {
+		return nil, nil
+	}
+	traces, err := vector.Diagnostics.DecodeTraces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode traces of vector %s: %w", file, err)
+	}
+	return traces, nil
+}
+
+// printTrace prints a call and its subcalls, prefixing every line with the
+// supplied indentation.
+func printTrace(w io.Writer, t schema.ExecutionTrace, indent string, gas bool) {
+	var line strings.Builder
+	if m := t.Message; m != nil {
+		fmt.Fprintf(&line, "%s → %s method=%d value=%s", m.From, m.To, m.Method, m.Value)
+	}
+	if r := t.Receipt; r != nil {
+		fmt.Fprintf(&line, " exit=%d gas=%d", r.ExitCode, r.GasUsed)
+		if len(r.ReturnValue) > 0 {
+			fmt.Fprintf(&line, " return=%s", r.ReturnValue)
+		}
+	}
+	if t.Error != "" {
+		fmt.Fprintf(&line, " error=%q", t.Error)
+	}
+	_, _ = fmt.Fprintf(w, "%s%s\n", indent, line.String())
+
+	if gas {
+		for _, gc := range t.GasCharges {
+			_, _ = fmt.Fprintf(w, "%s  ⛽ %s total=%d compute=%d storage=%d\n", indent, gc.Name, gc.TotalGas, gc.ComputeGas, gc.StorageGas)
+		}
+	}
+
+	for _, sub := range t.Subcalls {
+		printTrace(w, sub, indent+"  ", gas)
+	}
+}
@@ -1,49 +1,129 @@
+// Command validate checks test vectors against the JSON schema of this repo,
+// and optionally against the semantic rules enforced by
+// schema.TestVector#Validate.
+//
+// Usage:
+//
+//  go run ./cmd/validate [flags] [file or directory...]
+//
+// If no paths are supplied, the corpus/ directory of this repo is used.
+// Directories are walked recursively, and all .json files within them are
+// validated, except for the corpus index. Validation carries on after
+// failures; the command exits with a non-zero status if any vector is
+// invalid.
+//
+// Supported flags:
+//
+//  -schema <file>
+//		JSON schema to validate against; defaults to the schema.json of this
+//		repo.
+//
+//  -semantic
+//		also apply the semantic rules of schema.TestVector#Validate.
+//
+//  -format <human|json>
+//		output format of the report; defaults to human.
+//
+//  -out <file>
+//		file to write the report to; if omitted, the report is written to
+//		stdout.
+//
+//  -p <n>
+//		number of vectors to validate in parallel; defaults to the number of
+//		CPUs.
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
-	"strings"
+	"sync"
 
 	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/chenjianmei111/test-vectors/schema"
 )
 
 func main() {
-	sp := schemaPath()
-	schema := gojsonschema.NewReferenceLoader("file://" + schemaPath())
-	fmt.Printf("📖 loading schema from %s\n", sp)
+	var (
+		schemaFile string
+		semantic   bool
+		format     string
+		out        string
+		parallel   int
+	)
+	flag.StringVar(&schemaFile, "schema", schemaPath(), "JSON schema to validate against")
+	flag.BoolVar(&semantic, "semantic", false, "also apply the semantic rules of schema.TestVector#Validate")
+	flag.StringVar(&format, "format", "human", "output format of the report: human or json")
+	flag.StringVar(&out, "out", "", "file to write the report to; if omitted, the report is written to stdout")
+	flag.IntVar(&parallel, "p", runtime.NumCPU(), "number of vectors to validate in parallel")
+	flag.Parse()
 
-	cp := corpusRootPath()
-	fmt.Printf("🏛  walking the corpus at %s\n", cp)
+	reporter, ok := reporters[format]
+	if !ok {
+		log.Fatalf("unknown report format: %s", format)
+	}
 
-	err := filepath.Walk(cp, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !strings.HasSuffix(path, ".json") {
-			return nil
-		}
-		relPath := strings.Replace(path, cp+"/", "", 1)
-		doc := gojsonschema.NewReferenceLoader("file://" + path)
-		result, err := gojsonschema.Validate(schema, doc)
+	schemaFile, err := filepath.Abs(schemaFile)
+	if err != nil {
+		log.Fatalf("failed to resolve schema path: %s", err)
+	}
+	fmt.Fprintf(os.Stderr, "📖 loading schema from %s\n", schemaFile)
+	js, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://" + schemaFile))
+	if err != nil {
+		log.Fatalf("failed to load schema: %s", err)
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{corpusRootPath()}
+	}
+
+	files, err := schema.VectorFiles(paths...)
+	if err != nil {
+		log.Fatalf("failed to enumerate vectors: %s", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]Result, len(files))
+		sem     = make(chan struct{}, parallel)
+		v       = &Validator{Schema: js, Semantic: semantic}
+	)
+	for i, file := range files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, file string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = v.ValidateFile(file)
+		}(i, file)
+	}
+	wg.Wait()
+
+	w := os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
 		if err != nil {
-			return fmt.Errorf("validating vector %s: %w", relPath, err)
-		}
-		if !result.Valid() {
-			fmt.Printf("❌ %s\n", relPath)
-			for _, desc := range result.Errors() {
-				fmt.Printf("\t- %s\n", desc)
-			}
-			return fmt.Errorf("validating vector %s", relPath)
+			log.Fatalf("failed to create report file %s: %s", out, err)
 		}
-		fmt.Printf("✅ %s\n", relPath)
-		return nil
-	})
-	if err != nil {
-		panic(fmt.Errorf("walking corpus: %w", err))
+		defer f.Close()
+		w = f
+	}
+
+	if err := reporter(w, results); err != nil {
+		log.Fatalf("failed to write report: %s", err)
+	}
+
+	if s := summarize(results); s.Invalid > 0 {
+		// flush the report file before exiting.
+		_ = w.Sync()
+		fmt.Fprintf(os.Stderr, "%d vectors are invalid\n", s.Invalid)
+		os.Exit(1)
 	}
 }
 
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Reporter writes the results of a validation run in a particular format.
+type Reporter func(w io.Writer, results []Result) error
+
+// reporters enumerates the supported report formats.
+var reporters = map[string]Reporter{
+	"human": reportHuman,
+	"json":  reportJSON,
+}
+
+// Summary tallies the results of a validation run.
+type Summary struct {
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+}
+
+func summarize(results []Result) Summary {
+	s := Summary{Total: len(results)}
+	for _, r := range results {
+		if r.Valid {
+			s.Valid++
+		} else {
+			s.Invalid++
+		}
+	}
+	return s
+}
+
+func reportHuman(w io.Writer, results []Result) error {
+	for _, r := range results {
+		icon := "✅"
+		if !r.Valid {
+			icon = "❌"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", icon, r.File); err != nil {
+			return err
+		}
+		for _, e := range r.Errors {
+			if _, err := fmt.Fprintf(w, "\t- %s\n", e); err != nil {
+				return err
+			}
+		}
+	}
+	s := summarize(results)
+	_, err := fmt.Fprintf(w, "\ntotal: %d, valid: %d, invalid: %d\n", s.Total, s.Valid, s.Invalid)
+	return err
+}
+
+func reportJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(struct {
+		Summary Summary  `json:"summary"`
+		Results []Result `json:"results"`
+	}{summarize(results), results})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// Result is the outcome of validating a single vector file.
+type Result struct {
+	File   string   `json:"file"`
+	ID     string   `json:"id,omitempty"`
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Validator validates vector files.
+type Validator struct {
+	// Schema is the JSON schema to validate vectors against.
+	Schema *gojsonschema.Schema
+
+	// Semantic, if true, also applies the semantic rules of
+	// schema.TestVector#Validate to vectors that conform to the JSON schema.
+	Semantic bool
+}
+
+// ValidateFile validates the vector stored in the supplied file.
+func (v *Validator) ValidateFile(file string) Result {
+	res := Result{File: file}
+	fail := func(format string, args ...interface{}) Result {
+		res.Errors = append(res.Errors, fmt.Sprintf(format, args...))
+		return res
+	}
+
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fail("failed to read vector: %s", err)
+	}
+
+	result, err := v.Schema.Validate(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return fail("failed to validate vector against schema: %s", err)
+	}
+	for _, desc := range result.Errors() {
+		res.Errors = append(res.Errors, desc.String())
+	}
+
+	var vector schema.TestVector
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return fail("failed to parse vector: %s", err)
+	}
+	if vector.Meta != nil {
+		res.ID = vector.Meta.ID
+	}
+
+	// only apply semantic rules to vectors that are structurally sound.
+	if v.Semantic && len(res.Errors) == 0 {
+		switch err := vector.Validate().(type) {
+		case nil:
+		case schema.ValidationErrors:
+			for _, e := range err {
+				res.Errors = append(res.Errors, e.Error())
+			}
+		default:
+			res.Errors = append(res.Errors, err.Error())
+		}
+	}
+
+	res.Valid = len(res.Errors) == 0
+	return res
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// section is a titled group of differences.
+type section struct {
+	title string
+	lines []string
+}
+
+func (s *section) add(format string, args ...interface{}) {
+	s.lines = append(s.lines, fmt.Sprintf(format, args...))
+}
+
+// differ accumulates the differences between two vectors, grouped in
+// sections.
+type differ struct {
+	sections []*section
+}
+
+func (d *differ) section(title string) *section {
+	s := &section{title: title}
+	d.sections = append(d.sections, s)
+	return s
+}
+
+func (d *differ) empty() bool {
+	for _, s := range d.sections {
+		if len(s.lines) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *differ) print(w io.Writer) {
+	for _, s := range d.sections {
+		if len(s.lines) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s:\n", s.title)
+		for _, l := range s.lines {
+			_, _ = fmt.Fprintf(w, "  %s\n", l)
+		}
+	}
+}
+
+// diffVectors records the differences between vectors a and b.
+func (d *differ) diffVectors(a, b *schema.TestVector) error {
+	d.diffMetadata(a, b)
+
+	storeA, err := loadStore(a)
+	if err != nil {
+		return fmt.Errorf("vector a: %w", err)
+	}
+	storeB, err := loadStore(b)
+	if err != nil {
+		return fmt.Errorf("vector b: %w", err)
+	}
+
+	var preA, preB, postA, postB *schema.StateTree
+	if a.Pre != nil && b.Pre != nil {
+		preA, preB = a.Pre.StateTree, b.Pre.StateTree
+	}
+	if a.Post != nil && b.Post != nil {
+		postA, postB = a.Post.StateTree, b.Post.StateTree
+	}
+	if err := diffStateTrees(d.section("precondition state"), storeA, storeB, preA, preB); err != nil {
+		return fmt.Errorf("diffing precondition state: %w", err)
+	}
+	if err := diffStateTrees(d.section("postcondition state"), storeA, storeB, postA, postB); err != nil {
+		return fmt.Errorf("diffing postcondition state: %w", err)
+	}
+
+	d.diffReceipts(a, b)
+
+	return d.diffTraces(a, b)
+}
+
+func (d *differ) diffMetadata(a, b *schema.TestVector) {
+	s := d.section("vector")
+	if a.Class != b.Class {
+		s.add("class: %s → %s", a.Class, b.Class)
+	}
+
+	variants := func(v *schema.TestVector) string {
+		if v.Pre == nil {
+			return ""
+		}
+		ids := make([]string, 0, len(v.Pre.Variants))
+		for _, vr := range v.Pre.Variants {
+			ids = append(ids, fmt.Sprintf("%s@%d", vr.ID, vr.Epoch))
+		}
+		return strings.Join(ids, ", ")
+	}
+	if va, vb := variants(a), variants(b); va != vb {
+		s.add("variants: [%s] → [%s]", va, vb)
+	}
+
+	if la, lb := len(a.ApplyMessages), len(b.ApplyMessages); la != lb {
+		s.add("messages: %d → %d", la, lb)
+	}
+	if la, lb := len(a.ApplyTipsets), len(b.ApplyTipsets); la != lb {
+		s.add("tipsets: %d → %d", la, lb)
+	}
+	if la, lb := len(a.ApplyBlocks), len(b.ApplyBlocks); la != lb {
+		s.add("blocks: %d → %d", la, lb)
+	}
+}
+
+func (d *differ) diffReceipts(a, b *schema.TestVector) {
+	s := d.section("receipts")
+
+	var ra, rb []*schema.Receipt
+	if a.Post != nil {
+		ra = a.Post.Receipts
+	}
+	if b.Post != nil {
+		rb = b.Post.Receipts
+	}
+
+	for i := 0; i < len(ra) || i < len(rb); i++ {
+		switch {
+		case i >= len(ra):
+			s.add("+ [%d] %s", i, formatReceipt(rb[i]))
+		case i >= len(rb):
+			s.add("- [%d] %s", i, formatReceipt(ra[i]))
+		case ra[i] == nil || rb[i] == nil:
+			if (ra[i] == nil) != (rb[i] == nil) {
+				s.add("~ [%d] %s → %s", i, formatReceipt(ra[i]), formatReceipt(rb[i]))
+			}
+		default:
+			x, y := ra[i], rb[i]
+			if x.ExitCode != y.ExitCode {
+				s.add("~ [%d] exit_code: %d → %d", i, x.ExitCode, y.ExitCode)
+			}
+			if x.ReturnValue.String() != y.ReturnValue.String() {
+				s.add("~ [%d] return: %q → %q", i, x.ReturnValue, y.ReturnValue)
+			}
+			if x.GasUsed != y.GasUsed {
+				s.add("~ [%d] gas_used: %d → %d (%+d)", i, x.GasUsed, y.GasUsed, y.GasUsed-x.GasUsed)
+			}
+		}
+	}
+
+	if a.Post != nil && b.Post != nil {
+		ra, rb := a.Post.ReceiptsRoots, b.Post.ReceiptsRoots
+		for i := 0; i < len(ra) || i < len(rb); i++ {
+			switch {
+			case i >= len(ra):
+				s.add("+ receipts root [%d] %s", i, rb[i])
+			case i >= len(rb):
+				s.add("- receipts root [%d] %s", i, ra[i])
+			case !ra[i].Equals(rb[i]):
+				s.add("~ receipts root [%d] %s → %s", i, ra[i], rb[i])
+			}
+		}
+	}
+}
+
+func formatReceipt(r *schema.Receipt) string {
+	if r == nil {
+		return "<failed to apply>"
+	}
+	return fmt.Sprintf("exit_code=%d return=%q gas_used=%d", r.ExitCode, r.ReturnValue, r.GasUsed)
+}
+
+func (d *differ) diffTraces(a, b *schema.TestVector) error {
+	s := d.section("execution traces")
+
+	ta, err := decodeTraces(a.Diagnostics)
+	if err != nil {
+		return fmt.Errorf("vector a: %w", err)
+	}
+	tb, err := decodeTraces(b.Diagnostics)
+	if err != nil {
+		return fmt.Errorf("vector b: %w", err)
+	}
+
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		var la, lb []string
+		if i < len(ta) {
+			la = flattenTrace(ta[i], 0)
+		}
+		if i < len(tb) {
+			lb = flattenTrace(tb[i], 0)
+		}
+		if lines := lineDiff(la, lb, 2); len(lines) > 0 {
+			s.add("[%d]", i)
+			for _, l := range lines {
+				s.add("  %s", l)
+			}
+		}
+	}
+	return nil
+}
+
+// lineDiff returns a diff of the supplied lines, with lines prefixed by " ",
+// "-" or "+", and the specified amount of context around changes. It returns
+// nil if the inputs are equal.
+func lineDiff(a, b []string, context int) []string {
+	// longest common subsequence table.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var (
+		ops     []string
+		changed bool
+		i, j    int
+	)
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, "  "+a[i])
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, "- "+a[i])
+			i, changed = i+1, true
+		default:
+			ops = append(ops, "+ "+b[j])
+			j, changed = j+1, true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	// retain only changes and their context.
+	keep := make([]bool, len(ops))
+	for k, op := range ops {
+		if op[0] == ' ' {
+			continue
+		}
+		for c := k - context; c <= k+context; c++ {
+			if c >= 0 && c < len(ops) {
+				keep[c] = true
+			}
+		}
+	}
+	var out []string
+	for k, op := range ops {
+		if keep[k] {
+			out = append(out, op)
+		} else if k == 0 || keep[k-1] {
+			out = append(out, "  ...")
+		}
+	}
+	return out
+}
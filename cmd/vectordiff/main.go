@@ -0,0 +1,78 @@
+// Command vectordiff compares two test vectors, and reports their semantic
+// differences, in a form suitable for reviewing corpus changes.
+//
+// Usage:
+//
+//  go run ./cmd/vectordiff <a.json> <b.json>
+//
+// It decompresses the CARs of both vectors, and walks their pre- and
+// post-state trees, reporting actors that were added, removed, or whose
+// balance, nonce, code or head changed. For actors whose state can be decoded,
+// it also reports the changed state fields. Finally, it diffs receipts (exit
+// code, return value and gas used), and execution traces.
+//
+// The command exits with status 0 if the vectors are semantically equal, 1 if
+// they differ, and 2 on error.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s <a.json> <b.json>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fileA, fileB := flag.Arg(0), flag.Arg(1)
+	a, err := loadVector(fileA)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	b, err := loadVector(fileB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	d := &differ{}
+	if err := d.diffVectors(a, b); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if d.empty() {
+		fmt.Println("vectors are semantically equal")
+		return
+	}
+
+	fmt.Printf("--- %s\n+++ %s\n", fileA, fileB)
+	d.print(os.Stdout)
+	os.Exit(1)
+}
+
+func loadVector(file string) (*schema.TestVector, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vector %s: %w", file, err)
+	}
+	var vector schema.TestVector
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return nil, fmt.Errorf("failed to parse vector %s: %w", file, err)
+	}
+	return &vector, nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/lotus/chain/actors"
+	"github.com/chenjianmei111/lotus/chain/state"
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/lib/blockstore"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/ipld/go-car"
+
+	"github.com/chenjianmei111/test-vectors/gen/builders"
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// codeName returns the name of the builtin actor with the supplied code,
+// suffixed with its actors version if other than 0, or the code itself if
+// it's not a builtin actor.
+func codeName(code cid.Cid) string {
+	adapter, ok := builders.ActorsAdapterForCode(code)
+	if !ok {
+		return code.String()
+	}
+	name, _ := adapter.Codes().Name(code)
+	if v := adapter.Version(); v != actors.Version0 {
+		name = fmt.Sprintf("%s/%d", name, v)
+	}
+	return name
+}
+
+// loadStore loads the CAR of the vector into a fresh in-memory store.
+func loadStore(vector *schema.TestVector) (cbor.IpldStore, error) {
+	bs := blockstore.NewBlockstore(ds.NewMapDatastore())
+
+	gr, err := gzip.NewReader(bytes.NewReader(vector.CAR))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inflate CAR: %w", err)
+	}
+	defer gr.Close()
+
+	if _, err := car.LoadCar(bs, gr); err != nil {
+		return nil, fmt.Errorf("failed to load CAR: %w", err)
+	}
+	return cbor.NewCborStore(bs), nil
+}
+
+// loadActors returns all actors in the state tree with the supplied root.
+func loadActors(store cbor.IpldStore, root cid.Cid) (map[address.Address]types.Actor, error) {
+	tree, err := state.LoadStateTree(store, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state tree %s: %w", root, err)
+	}
+	actors := make(map[address.Address]types.Actor)
+	err = tree.ForEach(func(addr address.Address, act *types.Actor) error {
+		actors[addr] = *act
+		return nil
+	})
+	return actors, err
+}
+
+// diffStateTrees records the actor-level differences between two state trees.
+func diffStateTrees(s *section, storeA, storeB cbor.IpldStore, a, b *schema.StateTree) error {
+	if a == nil || b == nil {
+		if (a == nil) != (b == nil) {
+			s.add("state tree present in only one vector")
+		}
+		return nil
+	}
+	if a.RootCID.Equals(b.RootCID) {
+		return nil
+	}
+	s.add("root: %s → %s", a.RootCID, b.RootCID)
+
+	actorsA, err := loadActors(storeA, a.RootCID)
+	if err != nil {
+		return err
+	}
+	actorsB, err := loadActors(storeB, b.RootCID)
+	if err != nil {
+		return err
+	}
+
+	addrs := make([]address.Address, 0, len(actorsA)+len(actorsB))
+	for addr := range actorsA {
+		addrs = append(addrs, addr)
+	}
+	for addr := range actorsB {
+		if _, ok := actorsA[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].String() < addrs[j].String() })
+
+	for _, addr := range addrs {
+		actA, okA := actorsA[addr]
+		actB, okB := actorsB[addr]
+		switch {
+		case !okA:
+			s.add("+ %s (%s) balance=%s nonce=%d", addr, codeName(actB.Code), actB.Balance, actB.Nonce)
+			continue
+		case !okB:
+			s.add("- %s (%s) balance=%s nonce=%d", addr, codeName(actA.Code), actA.Balance, actA.Nonce)
+			continue
+		case actA.Balance.Equals(actB.Balance) && actA.Nonce == actB.Nonce && actA.Code.Equals(actB.Code) && actA.Head.Equals(actB.Head):
+			continue
+		}
+
+		s.add("~ %s (%s)", addr, codeName(actB.Code))
+		if !actA.Balance.Equals(actB.Balance) {
+			s.add("    balance: %s → %s", actA.Balance, actB.Balance)
+		}
+		if actA.Nonce != actB.Nonce {
+			s.add("    nonce: %d → %d", actA.Nonce, actB.Nonce)
+		}
+		if !actA.Code.Equals(actB.Code) {
+			s.add("    code: %s → %s", codeName(actA.Code), codeName(actB.Code))
+		}
+		if !actA.Head.Equals(actB.Head) {
+			s.add("    head: %s → %s", actA.Head, actB.Head)
+			if err := diffActorState(s, storeA, storeB, actA, actB); err != nil {
+				s.add("    state: failed to decode: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// diffActorState records the field-level differences between the states of
+// two builtin actors of the same code, decoded through the actors adapter of
+// their version.
+func diffActorState(s *section, storeA, storeB cbor.IpldStore, a, b types.Actor) error {
+	if !a.Code.Equals(b.Code) {
+		return nil
+	}
+	adapter, ok := builders.ActorsAdapterForCode(a.Code)
+	if !ok || adapter.NewState(a.Code) == nil {
+		return nil
+	}
+
+	load := func(store cbor.IpldStore, head cid.Cid) (interface{}, error) {
+		obj := adapter.NewState(a.Code)
+		if err := store.Get(context.Background(), head, obj); err != nil {
+			return nil, err
+		}
+		// round-trip through JSON to obtain a generic representation.
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		var ret interface{}
+		err = json.Unmarshal(raw, &ret)
+		return ret, err
+	}
+
+	stA, err := load(storeA, a.Head)
+	if err != nil {
+		return err
+	}
+	stB, err := load(storeB, b.Head)
+	if err != nil {
+		return err
+	}
+
+	var changes []string
+	diffValues("state", stA, stB, &changes)
+	for _, c := range changes {
+		s.add("    %s", c)
+	}
+	return nil
+}
+
+// diffValues recursively compares two generic JSON values, appending a line
+// per changed leaf to out.
+func diffValues(path string, a, b interface{}, out *[]string) {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(x)+len(y))
+			for k := range x {
+				keys = append(keys, k)
+			}
+			for k := range y {
+				if _, ok := x[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				diffValues(path+"."+k, x[k], y[k], out)
+			}
+			return
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			for i := 0; i < len(x) || i < len(y); i++ {
+				var ex, ey interface{}
+				if i < len(x) {
+					ex = x[i]
+				}
+				if i < len(y) {
+					ey = y[i]
+				}
+				diffValues(fmt.Sprintf("%s[%d]", path, i), ex, ey, out)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*out = append(*out, fmt.Sprintf("%s: %s → %s", path, formatValue(a), formatValue(b)))
+	}
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "<none>"
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	const max = 80
+	if s := string(raw); len(s) > max {
+		return s[:max] + "…"
+	}
+	return string(raw)
+}
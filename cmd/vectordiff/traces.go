@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// decodeTraces decodes the execution traces embedded in the supplied
+// diagnostics. It returns nil if there are no diagnostics.
+func decodeTraces(d *schema.Diagnostics) ([]schema.ExecutionTrace, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return d.DecodeTraces()
+}
+
+// flattenTrace renders an execution trace as one line per call, indented by
+// call depth.
+func flattenTrace(t schema.ExecutionTrace, depth int) []string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", depth))
+	if m := t.Message; m != nil {
+		fmt.Fprintf(&b, "%s -> %s method=%d value=%s", m.From, m.To, m.Method, m.Value)
+	}
+	if r := t.Receipt; r != nil {
+		fmt.Fprintf(&b, " exit=%d gas=%d", r.ExitCode, r.GasUsed)
+	}
+	if t.Error != "" {
+		fmt.Fprintf(&b, " error=%q", t.Error)
+	}
+
+	lines := []string{b.String()}
+	for _, sub := range t.Subcalls {
+		lines = append(lines, flattenTrace(sub, depth+1)...)
+	}
+	return lines
+}
@@ -1,27 +1,14 @@
 package builders
 
 import (
-	"context"
+	"crypto/sha256"
+	"fmt"
 
 	"github.com/chenjianmei111/go-address"
 	"github.com/chenjianmei111/go-state-types/abi"
 	"github.com/chenjianmei111/go-state-types/big"
-	"github.com/chenjianmei111/go-state-types/cbor"
-
-	"github.com/chenjianmei111/lotus/chain/actors"
-
-	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
-	account0 "github.com/chenjianmei111/specs-actors/actors/builtin/account"
-	miner0 "github.com/chenjianmei111/specs-actors/actors/builtin/miner"
-	power0 "github.com/chenjianmei111/specs-actors/actors/builtin/power"
-
-	builtin2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin"
-	account2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/account"
-	miner2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/miner"
-	power2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/power"
-
-	adt0 "github.com/chenjianmei111/specs-actors/actors/util/adt"
-	adt2 "github.com/chenjianmei111/specs-actors/v2/actors/util/adt"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
 )
 
 type Account struct {
@@ -37,6 +24,10 @@ type Miner struct {
 type Actors struct {
 	accounts []Account
 	miners   []Miner
+	created  []AddressHandle
+
+	// seeded tracks the miners whose sectors have been seeded.
+	seeded map[address.Address]bool
 
 	bc *BuilderCommon
 	st *StateTracker
@@ -66,6 +57,17 @@ func (a *Actors) Miners() []Miner {
 	return a.miners
 }
 
+// Created returns the handles of the actors created by init Exec messages
+// applied through the message vector builder, in creation order.
+func (a *Actors) Created() []AddressHandle {
+	return a.created
+}
+
+// registerCreated registers an actor created by an applied message.
+func (a *Actors) registerCreated(handle AddressHandle) {
+	a.created = append(a.created, handle)
+}
+
 // Count returns the number of accounts and miners registered.
 func (a *Actors) Count() int {
 	return len(a.accounts) + len(a.miners)
@@ -90,6 +92,11 @@ func (a *Actors) HandleFor(addr address.Address) AddressHandle {
 			return r.WorkerAddr
 		}
 	}
+	for _, h := range a.created {
+		if h.ID == addr || h.Robust == addr {
+			return h
+		}
+	}
 	a.bc.Assert.FailNowf("asked for handle of unknown actor", "actor: %s", addr)
 	return AddressHandle{} // will never reach here.
 }
@@ -138,146 +145,97 @@ func (a *Actors) Account(typ address.Protocol, balance abi.TokenAmount) AddressH
 		addr = a.bc.Wallet.NewBLSAccount()
 	}
 
-	var state cbor.Marshaler
-	var handle AddressHandle
-	switch a.st.ActorsVersion {
-	case actors.Version0:
-		state = &account0.State{Address: addr}
-		handle = a.st.CreateActor(builtin0.AccountActorCodeID, addr, balance, state)
-	case actors.Version2:
-		state = &account2.State{Address: addr}
-		handle = a.st.CreateActor(builtin2.AccountActorCodeID, addr, balance, state)
-	default:
-		panic("unknown actors version")
-	}
+	adapter := a.st.ActorsAdapter
+	handle := a.st.CreateActor(adapter.Codes().Account, addr, balance, adapter.AccountState(addr))
 
 	a.accounts = append(a.accounts, Account{handle, balance})
 	return handle
 }
 
+// RootVerifier creates the account of the root key of the verified registry,
+// at the RootVerifier ID address, with the supplied balance, and returns its
+// AddressHandle. The account is backed by a new SECP256K1 key, but that key is
+// not mapped to the ID address by the init actor, so the handle carries the
+// ID address in both positions.
+func (a *Actors) RootVerifier(balance abi.TokenAmount) AddressHandle {
+	key := a.bc.Wallet.NewSECP256k1Account()
+
+	adapter := a.st.ActorsAdapter
+	a.st.CreateActor(adapter.Codes().Account, RootVerifier, balance, adapter.AccountState(key))
+
+	handle := AddressHandle{ID: RootVerifier, Robust: RootVerifier}
+	a.accounts = append(a.accounts, Account{handle, balance})
+	return handle
+}
+
 type MinerActorCfg struct {
 	SealProofType  abi.RegisteredSealProof
 	PeriodBoundary abi.ChainEpoch
 	OwnerBalance   abi.TokenAmount
+
+	// Owner and Worker, if not nil, are existing accounts to use as the owner
+	// and worker of the miner. Otherwise, a new SECP256K1 owner account with
+	// OwnerBalance, and a new BLS worker account with no balance are created.
+	Owner, Worker *AddressHandle
+
+	// PeerID is the peer ID set in the miner info; it defaults to "test".
+	PeerID []byte
+	// Multiaddrs are the multiaddrs set in the miner info.
+	Multiaddrs [][]byte
+
+	// RawPower and QAPower are the raw byte and quality-adjusted power
+	// initially claimed by the miner; they default to zero. The totals of the
+	// storage power actor, its values for the current epoch, and its count of
+	// miners above the consensus minimum power, account for them.
+	RawPower, QAPower abi.StoragePower
 }
 
-// Miner creates an owner account, a worker account, and a miner actor with the
-// supplied configuration.
+// Miner creates a miner actor with the supplied configuration, along with its
+// owner and worker accounts unless existing ones are supplied. It registers
+// the miner with the storage power actor, claiming the configured power.
+//
+// The miner address is derived from the worker address, so that a worker can
+// be shared by several miners.
 func (a *Actors) Miner(cfg MinerActorCfg) Miner {
-	var (
-		owner  = a.Account(address.SECP256K1, cfg.OwnerBalance)
+	var owner, worker AddressHandle
+	if cfg.Owner != nil {
+		owner = *cfg.Owner
+	} else {
+		owner = a.Account(address.SECP256K1, cfg.OwnerBalance)
+	}
+	if cfg.Worker != nil {
+		worker = *cfg.Worker
+	} else {
 		worker = a.Account(address.BLS, big.Zero())
-
-		minerInfo cbor.Marshaler
-		state     cbor.Marshaler
-		err       error
-	)
-
-	switch a.st.ActorsVersion {
-	case actors.Version0:
-		minerInfo, err = miner0.ConstructMinerInfo(owner.ID, worker.ID, nil, []byte("test"), nil, cfg.SealProofType)
-	case actors.Version2:
-		minerInfo, err = miner2.ConstructMinerInfo(owner.ID, worker.ID, nil, []byte("test"), nil, cfg.SealProofType)
-	default:
-		panic("unknown actors version")
+	}
+	if cfg.PeerID == nil {
+		cfg.PeerID = []byte("test")
+	}
+	if cfg.RawPower.Int == nil {
+		cfg.RawPower = big.Zero()
+	}
+	if cfg.QAPower.Int == nil {
+		cfg.QAPower = big.Zero()
 	}
 
-	a.bc.Assert.NoError(err, "failed to construct miner info")
+	adapter := a.st.ActorsAdapter
 
-	infoCid, err := a.st.Stores.CBORStore.Put(context.Background(), minerInfo)
-	if err != nil {
-		panic(err)
-	}
+	state, err := adapter.MinerState(a.st, owner.ID, worker.ID, cfg)
+	a.bc.Assert.NoError(err, "failed to construct miner state")
 
-	// TODO allow an address to create multiple miners.
-	minerActorAddr := worker.NextActorAddress(0, 0)
-	var minerHandle AddressHandle
-	switch a.st.ActorsVersion {
-	case actors.Version0:
-		state, err = miner0.ConstructState(infoCid,
-			cfg.PeriodBoundary,
-			a.st.EmptyBitfieldCid,
-			a.st.EmptyArrayCid,
-			a.st.EmptyMapCid,
-			a.st.EmptyDeadlinesCid,
-			a.st.EmptyVestingFundsCid,
-		)
-		a.bc.Assert.NoError(err)
-		minerHandle = a.st.CreateActor(builtin0.StorageMinerActorCodeID, minerActorAddr, big.Zero(), state)
-
-		// next update the storage power actor to track the miner
-		var spa power0.State
-		a.st.ActorState(builtin0.StoragePowerActorAddr, &spa)
-
-		// set the miners claim
-		hm, err := adt0.AsMap(a.st.Stores.ADTStore, spa.Claims)
-		a.bc.Assert.NoError(err)
-
-		// add claim for the miner TODO: allow caller to specify.
-		err = hm.Put(abi.AddrKey(minerHandle.ID), &power0.Claim{
-			RawBytePower:    abi.NewStoragePower(0),
-			QualityAdjPower: abi.NewStoragePower(0),
-		})
-
-		// save the claim, update miner count
-		spa.Claims, err = hm.Root()
-		spa.MinerCount += 1
-
-		// update storage power actor's state in the tree
-		spaCid, err := a.st.Stores.CBORStore.Put(context.Background(), &spa)
-		a.bc.Assert.NoError(err)
-
-		// update spa header.
-		spaHeader := a.st.Header(builtin0.StoragePowerActorAddr)
-		spaHeader.Head = spaCid
-		err = a.st.StateTree.SetActor(builtin0.StoragePowerActorAddr, spaHeader)
-		a.bc.Assert.NoError(err)
-
-	case actors.Version2:
-		state, err = miner2.ConstructState(infoCid,
-			cfg.PeriodBoundary,
-			0, // different
-			a.st.EmptyBitfieldCid,
-			a.st.EmptyArrayCid,
-			a.st.EmptyMapCid,
-			a.st.EmptyDeadlinesCid,
-			a.st.EmptyVestingFundsCid,
-		)
-		a.bc.Assert.NoError(err)
-
-		minerHandle = a.st.CreateActor(builtin2.StorageMinerActorCodeID, minerActorAddr, big.Zero(), state)
-
-		// next update the storage power actor to track the miner
-		var spa power2.State
-		a.st.ActorState(builtin2.StoragePowerActorAddr, &spa)
-
-		// set the miners claim
-		hm, err := adt2.AsMap(a.st.Stores.ADTStore, spa.Claims)
-		a.bc.Assert.NoError(err)
-
-		// add claim for the miner TODO: allow caller to specify.
-		err = hm.Put(abi.AddrKey(minerHandle.ID), &power2.Claim{
-			RawBytePower:    abi.NewStoragePower(0),
-			QualityAdjPower: abi.NewStoragePower(0),
-		})
-
-		// save the claim, update miner count
-		spa.Claims, err = hm.Root()
-		spa.MinerCount += 1
-
-		// update storage power actor's state in the tree
-		spaCid, err := a.st.Stores.CBORStore.Put(context.Background(), &spa)
-		a.bc.Assert.NoError(err)
-
-		// update spa header.
-		spaHeader := a.st.Header(builtin2.StoragePowerActorAddr)
-		spaHeader.Head = spaCid
-		err = a.st.StateTree.SetActor(builtin2.StoragePowerActorAddr, spaHeader)
-		a.bc.Assert.NoError(err)
-
-	default:
-		panic("unknown actors version")
+	// derive the address from the number of miners this worker already has.
+	var n uint64
+	for _, m := range a.miners {
+		if m.WorkerAddr == worker {
+			n++
+		}
 	}
+	minerActorAddr := worker.NextActorAddress(n, 0)
+	minerHandle := a.st.CreateActor(adapter.Codes().Miner, minerActorAddr, big.Zero(), state)
+
+	// next update the storage power actor to track the miner.
+	err = adapter.RegisterMiner(a.st, minerHandle.ID, cfg)
+	a.bc.Assert.NoError(err, "failed to register miner with the storage power actor")
 
 	m := Miner{
 		MinerActorAddr: minerHandle,
@@ -298,3 +256,99 @@ func (a *Actors) MinerN(cfg MinerActorCfg, miners ...*Miner) {
 		*m = a.Miner(cfg)
 	}
 }
+
+// SectorCfg describes a sector seeded in a miner through Actors.MinerSectors.
+type SectorCfg struct {
+	Number abi.SectorNumber
+
+	// Epoch is the activation epoch of an active sector, or the precommit
+	// epoch of a precommitted sector.
+	Epoch      abi.ChainEpoch
+	Expiration abi.ChainEpoch
+
+	DealIDs                        []abi.DealID
+	DealWeight, VerifiedDealWeight abi.DealWeight
+
+	// Collateral is the initial pledge of an active sector, or the precommit
+	// deposit of a precommitted sector; it defaults to zero.
+	Collateral abi.TokenAmount
+}
+
+// MinerSectorsCfg configures the sectors seeded in a miner through
+// Actors.MinerSectors.
+type MinerSectorsCfg struct {
+	// PreCommitted are sectors that have been precommitted, but not proven.
+	PreCommitted []SectorCfg
+
+	// Active are sectors that have been proven, and are active. They are
+	// assigned, in order, to the partitions of the Deadline deadline, filling
+	// one partition before moving on to the next one.
+	Active []SectorCfg
+
+	// Deadline is the index of the deadline active sectors are assigned to.
+	Deadline uint64
+}
+
+// MinerSectors seeds the supplied miner with precommitted and active sectors.
+// It populates the sector infos, partitions and deadlines of the miner, and
+// locks the precommit deposits and initial pledges, adding them to the miner
+// balance. The power of the active sectors is added to the claim of the miner
+// in the storage power actor, along with the pledge total. No proofs are
+// involved: sealed CIDs are fake, but deterministic.
+//
+// The miner is also enrolled for its first proving deadline cron event, at the
+// epoch the miner constructor of its actors version enrolls it at: the epoch
+// before the start of the proving period with actors v0, and the last epoch of
+// the first deadline of the proving period with actors v2.
+//
+// Precommits are not scheduled for expiry. Sectors can only be seeded once
+// per miner.
+func (a *Actors) MinerSectors(m Miner, cfg MinerSectorsCfg) {
+	a.bc.Assert.False(a.seeded[m.MinerActorAddr.ID], "sectors already seeded for miner %s", m.MinerActorAddr.ID)
+	if a.seeded == nil {
+		a.seeded = make(map[address.Address]bool)
+	}
+	a.seeded[m.MinerActorAddr.ID] = true
+
+	cfg.PreCommitted = withSectorDefaults(cfg.PreCommitted)
+	cfg.Active = withSectorDefaults(cfg.Active)
+
+	locked, err := a.st.ActorsAdapter.SeedSectors(a.st, m.MinerActorAddr.ID, cfg)
+	a.bc.Assert.NoError(err, "failed to seed sectors for miner %s", m.MinerActorAddr.ID)
+
+	// back the locked funds with balance.
+	actor := a.st.Header(m.MinerActorAddr.ID)
+	actor.Balance = big.Add(actor.Balance, locked)
+	err = a.st.StateTree.SetActor(m.MinerActorAddr.ID, actor)
+	a.bc.Assert.NoError(err, "failed to update the balance of miner %s", m.MinerActorAddr.ID)
+}
+
+// withSectorDefaults returns a copy of the supplied sectors, with defaults
+// set for the unset amounts.
+func withSectorDefaults(sectors []SectorCfg) []SectorCfg {
+	ret := make([]SectorCfg, 0, len(sectors))
+	for _, s := range sectors {
+		if s.Collateral.Int == nil {
+			s.Collateral = big.Zero()
+		}
+		if s.DealWeight.Int == nil {
+			s.DealWeight = big.Zero()
+		}
+		if s.VerifiedDealWeight.Int == nil {
+			s.VerifiedDealWeight = big.Zero()
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// fakeSealedCID returns a deterministic sealed CID with the supplied prefix,
+// for a sector of a miner. It does not commit to any actual replica.
+func fakeSealedCID(prefix cid.Prefix, miner address.Address, n abi.SectorNumber) (cid.Cid, error) {
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", miner, n)))
+	h, err := multihash.Encode(digest[:], prefix.MhType)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return cid.NewCidV1(prefix.Codec, h), nil
+}
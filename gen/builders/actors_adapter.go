@@ -0,0 +1,198 @@
+package builders
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+
+	"github.com/chenjianmei111/lotus/chain/actors"
+
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+)
+
+// ActorsAdapter encapsulates everything that varies across actors versions
+// when building the state of a vector: empty structures, the zero state,
+// account and miner construction, and code CIDs.
+//
+// An adapter is registered per actors version through RegisterActorsAdapter,
+// usually from an init function. ProtocolVersions reference the adapter of
+// their actors version through ProtocolVersion#ActorsAdapter. Supporting a new
+// actors version is a matter of registering an adapter for it.
+type ActorsAdapter interface {
+	// Version returns the actors version this adapter is for.
+	Version() actors.Version
+
+	// Codes returns the code CIDs of the builtin actors.
+	Codes() ActorCodes
+
+	// Methods returns the method numbers of the builtin actors.
+	Methods() ActorMethods
+
+	// EncodeParams encodes the supplied method params for this actors
+	// version. Params are supplied as the actors v0 types wherever their
+	// encoding is the same across versions, and as the types of the latest
+	// version otherwise (see messages_typed.go). Adapters convert the latter
+	// to their own encoding, and verify that the former encode as their own
+	// params do.
+	EncodeParams(params cbor.Marshaler) ([]byte, error)
+
+	// NewState returns an empty state object of the builtin actor with the
+	// supplied code, to decode its state into, or nil if the code is not one
+	// of this version, or the actor has no state worth decoding.
+	NewState(code cid.Cid) cbor.Unmarshaler
+
+	// EmptyMultimap stores an empty multimap, and returns its root.
+	EmptyMultimap(st *StateTracker) (cid.Cid, error)
+
+	// EmptyDeadline returns an empty miner deadline, whose arrays have the
+	// supplied root.
+	EmptyDeadline(emptyArray cid.Cid) cbor.Marshaler
+
+	// EmptyVestingFunds returns empty miner vesting funds.
+	EmptyVestingFunds() cbor.Marshaler
+
+	// ZeroState returns the singleton system actors that make up the zero
+	// state tree. The tracker's empty structures are initialized by then.
+	ZeroState(st *StateTracker) []ActorState
+
+	// AccountState returns the state of an account actor with the supplied
+	// pubkey address.
+	AccountState(addr address.Address) cbor.Marshaler
+
+	// MinerState stores the info of a new miner actor, and returns its state.
+	MinerState(st *StateTracker, owner, worker address.Address, cfg MinerActorCfg) (cbor.Marshaler, error)
+
+	// RegisterMiner registers a miner actor with the storage power actor,
+	// claiming the power in the supplied configuration.
+	RegisterMiner(st *StateTracker, miner address.Address, cfg MinerActorCfg) error
+
+	// SeedSectors adds the supplied sectors to the state of a miner actor, and
+	// accounts for their power and pledge in the storage power actor. It
+	// returns the funds locked in the miner, which the caller must add to its
+	// balance.
+	SeedSectors(st *StateTracker, miner address.Address, cfg MinerSectorsCfg) (abi.TokenAmount, error)
+
+	// SeedDeal adds the supplied deal to the state of the storage market
+	// actor, published and activated at the supplied epoch, escrowing and
+	// locking the balances it requires for both parties. It returns the ID of
+	// the deal, and the funds escrowed, which the caller must add to the
+	// balance of the market actor.
+	//
+	// As with the params of typed calls, the proposal is supplied as the
+	// actors v0 type, as its encoding is the same across versions. Adapters
+	// convert it to their own type by transcoding it, which verifies that.
+	SeedDeal(st *StateTracker, proposal market0.DealProposal, activation abi.ChainEpoch) (abi.DealID, abi.TokenAmount, error)
+
+	// EnrollCronEvent enrolls a deferred cron event for a miner actor in the
+	// storage power actor, to be delivered at the supplied epoch with the
+	// supplied payload.
+	EnrollCronEvent(st *StateTracker, miner address.Address, epoch abi.ChainEpoch, payload []byte) error
+
+	// ProvingDeadlineCronPayload returns the payload of the cron event through
+	// which a miner actor processes the end of its current proving deadline.
+	ProvingDeadlineCronPayload() ([]byte, error)
+
+	// CronEvents returns the cron events enrolled in the storage power actor.
+	CronEvents(st *StateTracker) ([]CronEvent, error)
+}
+
+// CronEvent is a deferred cron event enrolled in the storage power actor.
+type CronEvent struct {
+	Epoch   abi.ChainEpoch
+	Miner   address.Address
+	Payload []byte
+}
+
+// ActorCodes are the code CIDs of the builtin actors of an actors version.
+type ActorCodes struct {
+	System           cid.Cid
+	Init             cid.Cid
+	Cron             cid.Cid
+	Account          cid.Cid
+	Reward           cid.Cid
+	Power            cid.Cid
+	Market           cid.Cid
+	Miner            cid.Cid
+	Multisig         cid.Cid
+	PaymentChannel   cid.Cid
+	VerifiedRegistry cid.Cid
+}
+
+// Name returns the name of the builtin actor with the supplied code, or false
+// if the code is not one of these.
+func (c ActorCodes) Name(code cid.Cid) (string, bool) {
+	switch code {
+	case c.System:
+		return "system", true
+	case c.Init:
+		return "init", true
+	case c.Cron:
+		return "cron", true
+	case c.Account:
+		return "account", true
+	case c.Reward:
+		return "reward", true
+	case c.Power:
+		return "storagepower", true
+	case c.Market:
+		return "storagemarket", true
+	case c.Miner:
+		return "storageminer", true
+	case c.Multisig:
+		return "multisig", true
+	case c.PaymentChannel:
+		return "paymentchannel", true
+	case c.VerifiedRegistry:
+		return "verifiedregistry", true
+	}
+	return "", false
+}
+
+var (
+	actorsAdaptersLk sync.RWMutex
+	actorsAdapters   = make(map[actors.Version]ActorsAdapter)
+)
+
+// RegisterActorsAdapter registers the adapter for its actors version. It
+// panics if an adapter is already registered for that version.
+func RegisterActorsAdapter(adapter ActorsAdapter) {
+	actorsAdaptersLk.Lock()
+	defer actorsAdaptersLk.Unlock()
+
+	v := adapter.Version()
+	if _, ok := actorsAdapters[v]; ok {
+		panic(fmt.Sprintf("actors adapter already registered for actors version %d", v))
+	}
+	actorsAdapters[v] = adapter
+}
+
+// GetActorsAdapter returns the adapter registered for the supplied actors
+// version. It panics if there is none.
+func GetActorsAdapter(v actors.Version) ActorsAdapter {
+	actorsAdaptersLk.RLock()
+	defer actorsAdaptersLk.RUnlock()
+
+	adapter, ok := actorsAdapters[v]
+	if !ok {
+		panic(fmt.Sprintf("no actors adapter registered for actors version %d", v))
+	}
+	return adapter
+}
+
+// ActorsAdapterForCode returns the registered adapter of the actors version
+// the supplied builtin actor code belongs to, or false if there is none.
+func ActorsAdapterForCode(code cid.Cid) (ActorsAdapter, bool) {
+	actorsAdaptersLk.RLock()
+	defer actorsAdaptersLk.RUnlock()
+
+	for _, adapter := range actorsAdapters {
+		if _, ok := adapter.Codes().Name(code); ok {
+			return adapter, true
+		}
+	}
+	return nil, false
+}
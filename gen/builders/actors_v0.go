@@ -0,0 +1,595 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+
+	"github.com/chenjianmei111/lotus/chain/actors"
+
+	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
+	account0 "github.com/chenjianmei111/specs-actors/actors/builtin/account"
+	cron0 "github.com/chenjianmei111/specs-actors/actors/builtin/cron"
+	init0 "github.com/chenjianmei111/specs-actors/actors/builtin/init"
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+	miner0 "github.com/chenjianmei111/specs-actors/actors/builtin/miner"
+	multisig0 "github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+	paych0 "github.com/chenjianmei111/specs-actors/actors/builtin/paych"
+	power0 "github.com/chenjianmei111/specs-actors/actors/builtin/power"
+	reward0 "github.com/chenjianmei111/specs-actors/actors/builtin/reward"
+	system0 "github.com/chenjianmei111/specs-actors/actors/builtin/system"
+	verifreg0 "github.com/chenjianmei111/specs-actors/actors/builtin/verifreg"
+	proof0 "github.com/chenjianmei111/specs-actors/actors/runtime/proof"
+	adt0 "github.com/chenjianmei111/specs-actors/actors/util/adt"
+	smoothing0 "github.com/chenjianmei111/specs-actors/actors/util/smoothing"
+
+	miner2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/miner"
+	multisig2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/multisig"
+)
+
+func init() {
+	RegisterActorsAdapter(actorsV0{})
+}
+
+// actorsV0 is the ActorsAdapter for specs-actors v0.
+type actorsV0 struct{}
+
+var _ ActorsAdapter = actorsV0{}
+
+func (actorsV0) Version() actors.Version {
+	return actors.Version0
+}
+
+func (actorsV0) Codes() ActorCodes {
+	return ActorCodes{
+		System:           builtin0.SystemActorCodeID,
+		Init:             builtin0.InitActorCodeID,
+		Cron:             builtin0.CronActorCodeID,
+		Account:          builtin0.AccountActorCodeID,
+		Reward:           builtin0.RewardActorCodeID,
+		Power:            builtin0.StoragePowerActorCodeID,
+		Market:           builtin0.StorageMarketActorCodeID,
+		Miner:            builtin0.StorageMinerActorCodeID,
+		Multisig:         builtin0.MultisigActorCodeID,
+		PaymentChannel:   builtin0.PaymentChannelActorCodeID,
+		VerifiedRegistry: builtin0.VerifiedRegistryActorCodeID,
+	}
+}
+
+func (actorsV0) Methods() ActorMethods {
+	var m ActorMethods
+
+	m.Account.Constructor = builtin0.MethodsAccount.Constructor
+	m.Account.PubkeyAddress = builtin0.MethodsAccount.PubkeyAddress
+
+	m.Init.Constructor = builtin0.MethodsInit.Constructor
+	m.Init.Exec = builtin0.MethodsInit.Exec
+
+	m.Cron.Constructor = builtin0.MethodsCron.Constructor
+	m.Cron.EpochTick = builtin0.MethodsCron.EpochTick
+
+	m.Reward.Constructor = builtin0.MethodsReward.Constructor
+	m.Reward.AwardBlockReward = builtin0.MethodsReward.AwardBlockReward
+	m.Reward.ThisEpochReward = builtin0.MethodsReward.ThisEpochReward
+	m.Reward.UpdateNetworkKPI = builtin0.MethodsReward.UpdateNetworkKPI
+
+	m.Multisig.Constructor = builtin0.MethodsMultisig.Constructor
+	m.Multisig.Propose = builtin0.MethodsMultisig.Propose
+	m.Multisig.Approve = builtin0.MethodsMultisig.Approve
+	m.Multisig.Cancel = builtin0.MethodsMultisig.Cancel
+	m.Multisig.AddSigner = builtin0.MethodsMultisig.AddSigner
+	m.Multisig.RemoveSigner = builtin0.MethodsMultisig.RemoveSigner
+	m.Multisig.SwapSigner = builtin0.MethodsMultisig.SwapSigner
+	m.Multisig.ChangeNumApprovalsThreshold = builtin0.MethodsMultisig.ChangeNumApprovalsThreshold
+	// LockBalance was introduced in actors v2.
+
+	m.Paych.Constructor = builtin0.MethodsPaych.Constructor
+	m.Paych.UpdateChannelState = builtin0.MethodsPaych.UpdateChannelState
+	m.Paych.Settle = builtin0.MethodsPaych.Settle
+	m.Paych.Collect = builtin0.MethodsPaych.Collect
+
+	m.Market.Constructor = builtin0.MethodsMarket.Constructor
+	m.Market.AddBalance = builtin0.MethodsMarket.AddBalance
+	m.Market.WithdrawBalance = builtin0.MethodsMarket.WithdrawBalance
+	m.Market.PublishStorageDeals = builtin0.MethodsMarket.PublishStorageDeals
+	m.Market.VerifyDealsForActivation = builtin0.MethodsMarket.VerifyDealsForActivation
+	m.Market.ActivateDeals = builtin0.MethodsMarket.ActivateDeals
+	m.Market.OnMinerSectorsTerminate = builtin0.MethodsMarket.OnMinerSectorsTerminate
+	m.Market.ComputeDataCommitment = builtin0.MethodsMarket.ComputeDataCommitment
+	m.Market.CronTick = builtin0.MethodsMarket.CronTick
+
+	m.Power.Constructor = builtin0.MethodsPower.Constructor
+	m.Power.CreateMiner = builtin0.MethodsPower.CreateMiner
+	m.Power.UpdateClaimedPower = builtin0.MethodsPower.UpdateClaimedPower
+	m.Power.EnrollCronEvent = builtin0.MethodsPower.EnrollCronEvent
+	m.Power.OnEpochTickEnd = builtin0.MethodsPower.OnEpochTickEnd
+	m.Power.UpdatePledgeTotal = builtin0.MethodsPower.UpdatePledgeTotal
+	m.Power.OnConsensusFault = builtin0.MethodsPower.OnConsensusFault
+	m.Power.SubmitPoRepForBulkVerify = builtin0.MethodsPower.SubmitPoRepForBulkVerify
+	m.Power.CurrentTotalPower = builtin0.MethodsPower.CurrentTotalPower
+
+	m.Miner.Constructor = builtin0.MethodsMiner.Constructor
+	m.Miner.ControlAddresses = builtin0.MethodsMiner.ControlAddresses
+	m.Miner.ChangeWorkerAddress = builtin0.MethodsMiner.ChangeWorkerAddress
+	m.Miner.ChangePeerID = builtin0.MethodsMiner.ChangePeerID
+	m.Miner.SubmitWindowedPoSt = builtin0.MethodsMiner.SubmitWindowedPoSt
+	m.Miner.PreCommitSector = builtin0.MethodsMiner.PreCommitSector
+	m.Miner.ProveCommitSector = builtin0.MethodsMiner.ProveCommitSector
+	m.Miner.ExtendSectorExpiration = builtin0.MethodsMiner.ExtendSectorExpiration
+	m.Miner.TerminateSectors = builtin0.MethodsMiner.TerminateSectors
+	m.Miner.DeclareFaults = builtin0.MethodsMiner.DeclareFaults
+	m.Miner.DeclareFaultsRecovered = builtin0.MethodsMiner.DeclareFaultsRecovered
+	m.Miner.OnDeferredCronEvent = builtin0.MethodsMiner.OnDeferredCronEvent
+	m.Miner.CheckSectorProven = builtin0.MethodsMiner.CheckSectorProven
+	m.Miner.AddLockedFund = builtin0.MethodsMiner.AddLockedFund
+	m.Miner.ReportConsensusFault = builtin0.MethodsMiner.ReportConsensusFault
+	m.Miner.WithdrawBalance = builtin0.MethodsMiner.WithdrawBalance
+	m.Miner.ConfirmSectorProofsValid = builtin0.MethodsMiner.ConfirmSectorProofsValid
+	m.Miner.ChangeMultiaddrs = builtin0.MethodsMiner.ChangeMultiaddrs
+	m.Miner.CompactPartitions = builtin0.MethodsMiner.CompactPartitions
+	m.Miner.CompactSectorNumbers = builtin0.MethodsMiner.CompactSectorNumbers
+	// ApplyRewards, ConfirmUpdateWorkerKey, RepayDebt and ChangeOwnerAddress
+	// were introduced in actors v2.
+
+	m.VerifiedRegistry.Constructor = builtin0.MethodsVerifiedRegistry.Constructor
+	m.VerifiedRegistry.AddVerifier = builtin0.MethodsVerifiedRegistry.AddVerifier
+	m.VerifiedRegistry.RemoveVerifier = builtin0.MethodsVerifiedRegistry.RemoveVerifier
+	m.VerifiedRegistry.AddVerifiedClient = builtin0.MethodsVerifiedRegistry.AddVerifiedClient
+	m.VerifiedRegistry.UseBytes = builtin0.MethodsVerifiedRegistry.UseBytes
+	m.VerifiedRegistry.RestoreBytes = builtin0.MethodsVerifiedRegistry.RestoreBytes
+	return m
+}
+
+func (actorsV0) EncodeParams(params cbor.Marshaler) ([]byte, error) {
+	switch p := params.(type) {
+	case *miner2.SubmitWindowedPoStParams:
+		// actors v0 take no chain commitment.
+		v0 := &miner0.SubmitWindowedPoStParams{Deadline: p.Deadline}
+		for _, part := range p.Partitions {
+			v0.Partitions = append(v0.Partitions, miner0.PoStPartition{Index: part.Index, Skipped: part.Skipped})
+		}
+		for _, pr := range p.Proofs {
+			v0.Proofs = append(v0.Proofs, proof0.PoStProof{PoStProof: pr.PoStProof, ProofBytes: pr.ProofBytes})
+		}
+		params = v0
+	case *multisig2.ConstructorParams:
+		// actors v0 take no vesting start.
+		if p.StartEpoch != 0 {
+			return nil, fmt.Errorf("actors v0 multisigs vest from the epoch of creation; the vesting start must be 0")
+		}
+		params = &multisig0.ConstructorParams{
+			Signers:               p.Signers,
+			NumApprovalsThreshold: p.NumApprovalsThreshold,
+			UnlockDuration:        p.UnlockDuration,
+		}
+	}
+	return Serialize(params)
+}
+
+func (actorsV0) NewState(code cid.Cid) cbor.Unmarshaler {
+	switch code {
+	case builtin0.AccountActorCodeID:
+		return new(account0.State)
+	case builtin0.CronActorCodeID:
+		return new(cron0.State)
+	case builtin0.InitActorCodeID:
+		return new(init0.State)
+	case builtin0.StorageMarketActorCodeID:
+		return new(market0.State)
+	case builtin0.StorageMinerActorCodeID:
+		return new(miner0.State)
+	case builtin0.MultisigActorCodeID:
+		return new(multisig0.State)
+	case builtin0.PaymentChannelActorCodeID:
+		return new(paych0.State)
+	case builtin0.StoragePowerActorCodeID:
+		return new(power0.State)
+	case builtin0.RewardActorCodeID:
+		return new(reward0.State)
+	case builtin0.VerifiedRegistryActorCodeID:
+		return new(verifreg0.State)
+	}
+	return nil
+}
+
+func (actorsV0) EmptyMultimap(st *StateTracker) (cid.Cid, error) {
+	return adt0.MakeEmptyMultimap(st.Stores.ADTStore).Root()
+}
+
+func (actorsV0) EmptyDeadline(emptyArray cid.Cid) cbor.Marshaler {
+	return miner0.ConstructDeadline(emptyArray)
+}
+
+func (actorsV0) EmptyVestingFunds() cbor.Marshaler {
+	return miner0.ConstructVestingFunds()
+}
+
+func (actorsV0) ZeroState(st *StateTracker) []ActorState {
+	return []ActorState{{
+		Addr:    builtin0.InitActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.InitActorCodeID,
+		State:   init0.ConstructState(st.EmptyMapCid, "chain-validation"),
+	}, {
+		Addr:    builtin0.RewardActorAddr,
+		Balance: TotalNetworkBalance,
+		Code:    builtin0.RewardActorCodeID,
+		State:   reward0.ConstructState(big.Zero()),
+	}, {
+		Addr:    builtin0.BurntFundsActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.AccountActorCodeID,
+		State:   &account0.State{Address: builtin0.BurntFundsActorAddr},
+	}, {
+		Addr:    builtin0.StoragePowerActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.StoragePowerActorCodeID,
+		State:   power0.ConstructState(st.EmptyMapCid, st.EmptyMultiMapCid),
+	}, {
+		Addr:    builtin0.StorageMarketActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.StorageMarketActorCodeID,
+		State:   market0.ConstructState(st.EmptyArrayCid, st.EmptyMapCid, st.EmptyMultiMapCid),
+	}, {
+		Addr:    builtin0.SystemActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.SystemActorCodeID,
+		State:   new(system0.State),
+	}, {
+		Addr:    builtin0.CronActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.CronActorCodeID,
+		State:   cron0.ConstructState(cron0.BuiltInEntries()),
+	}, {
+		Addr:    builtin0.VerifiedRegistryActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin0.VerifiedRegistryActorCodeID,
+		State:   verifreg0.ConstructState(st.EmptyMapCid, RootVerifier),
+	}}
+}
+
+func (actorsV0) AccountState(addr address.Address) cbor.Marshaler {
+	return &account0.State{Address: addr}
+}
+
+func (actorsV0) MinerState(st *StateTracker, owner, worker address.Address, cfg MinerActorCfg) (cbor.Marshaler, error) {
+	info, err := miner0.ConstructMinerInfo(owner, worker, nil, cfg.PeerID, cfg.Multiaddrs, cfg.SealProofType)
+	if err != nil {
+		return nil, err
+	}
+	infoCid, err := st.Stores.CBORStore.Put(context.Background(), info)
+	if err != nil {
+		return nil, err
+	}
+	return miner0.ConstructState(infoCid,
+		cfg.PeriodBoundary,
+		st.EmptyBitfieldCid,
+		st.EmptyArrayCid,
+		st.EmptyMapCid,
+		st.EmptyDeadlinesCid,
+		st.EmptyVestingFundsCid,
+	)
+}
+
+func (actorsV0) RegisterMiner(st *StateTracker, miner address.Address, cfg MinerActorCfg) error {
+	var spa power0.State
+	st.ActorState(builtin0.StoragePowerActorAddr, &spa)
+
+	claims, err := adt0.AsMap(st.Stores.ADTStore, spa.Claims)
+	if err != nil {
+		return err
+	}
+	err = claims.Put(abi.AddrKey(miner), &power0.Claim{
+		RawBytePower:    abi.NewStoragePower(0),
+		QualityAdjPower: abi.NewStoragePower(0),
+	})
+	if err != nil {
+		return err
+	}
+	if spa.Claims, err = claims.Root(); err != nil {
+		return err
+	}
+	spa.MinerCount += 1
+
+	// claim the initial power through the accounting of the actor itself,
+	// which maintains the totals and the count of miners above the consensus
+	// minimum power.
+	if !cfg.RawPower.IsZero() || !cfg.QAPower.IsZero() {
+		if err := spa.AddToClaim(st.Stores.ADTStore, miner, cfg.RawPower, cfg.QAPower); err != nil {
+			return err
+		}
+		updateThisEpoch0(&spa)
+	}
+
+	return st.UpdateActorState(builtin0.StoragePowerActorAddr, &spa)
+}
+
+// updateThisEpoch0 brings the values of the storage power actor state for
+// the current epoch in line with its totals, as its cron tick would have at the
+// end of the previous epoch: the raw byte and quality-adjusted power, the
+// smoothed estimate of the quality-adjusted power, and the pledge collateral.
+// The estimate is reset to the current quality-adjusted power, with no
+// velocity, as there's no history to filter.
+func updateThisEpoch0(spa *power0.State) {
+	spa.ThisEpochRawBytePower, spa.ThisEpochQualityAdjPower = power0.CurrentTotalPower(spa)
+	spa.ThisEpochQAPowerSmoothed = smoothing0.NewEstimate(spa.ThisEpochQualityAdjPower, big.Zero())
+	spa.ThisEpochPledgeCollateral = spa.TotalPledgeCollateral
+}
+
+func (actorsV0) SeedSectors(st *StateTracker, miner address.Address, cfg MinerSectorsCfg) (abi.TokenAmount, error) {
+	var (
+		store  = st.Stores.ADTStore
+		locked = big.Zero()
+		pledge = big.Zero()
+		mst    miner0.State
+	)
+	st.ActorState(miner, &mst)
+
+	info, err := mst.GetInfo(store)
+	if err != nil {
+		return big.Zero(), err
+	}
+
+	for _, s := range cfg.PreCommitted {
+		sealed, err := fakeSealedCID(miner0.SealedCIDPrefix, miner, s.Number)
+		if err != nil {
+			return big.Zero(), err
+		}
+		if err := mst.AllocateSectorNumber(store, s.Number); err != nil {
+			return big.Zero(), err
+		}
+		err = mst.PutPrecommittedSector(store, &miner0.SectorPreCommitOnChainInfo{
+			Info: miner0.SectorPreCommitInfo{
+				SealProof:     info.SealProofType,
+				SectorNumber:  s.Number,
+				SealedCID:     sealed,
+				SealRandEpoch: s.Epoch - 1,
+				DealIDs:       s.DealIDs,
+				Expiration:    s.Expiration,
+			},
+			PreCommitDeposit:   s.Collateral,
+			PreCommitEpoch:     s.Epoch,
+			DealWeight:         s.DealWeight,
+			VerifiedDealWeight: s.VerifiedDealWeight,
+		})
+		if err != nil {
+			return big.Zero(), err
+		}
+		mst.PreCommitDeposits = big.Add(mst.PreCommitDeposits, s.Collateral)
+		locked = big.Add(locked, s.Collateral)
+	}
+
+	sectors := make([]*miner0.SectorOnChainInfo, 0, len(cfg.Active))
+	for _, s := range cfg.Active {
+		sealed, err := fakeSealedCID(miner0.SealedCIDPrefix, miner, s.Number)
+		if err != nil {
+			return big.Zero(), err
+		}
+		if err := mst.AllocateSectorNumber(store, s.Number); err != nil {
+			return big.Zero(), err
+		}
+		sectors = append(sectors, &miner0.SectorOnChainInfo{
+			SectorNumber:          s.Number,
+			SealProof:             info.SealProofType,
+			SealedCID:             sealed,
+			DealIDs:               s.DealIDs,
+			Activation:            s.Epoch,
+			Expiration:            s.Expiration,
+			DealWeight:            s.DealWeight,
+			VerifiedDealWeight:    s.VerifiedDealWeight,
+			InitialPledge:         s.Collateral,
+			ExpectedDayReward:     big.Zero(),
+			ExpectedStoragePledge: big.Zero(),
+		})
+		pledge = big.Add(pledge, s.Collateral)
+	}
+
+	power := miner0.NewPowerPairZero()
+	if len(sectors) > 0 {
+		if err := mst.PutSectors(store, sectors...); err != nil {
+			return big.Zero(), err
+		}
+		deadlines, err := mst.LoadDeadlines(store)
+		if err != nil {
+			return big.Zero(), err
+		}
+		dl, err := deadlines.LoadDeadline(store, cfg.Deadline)
+		if err != nil {
+			return big.Zero(), err
+		}
+		quant := mst.QuantSpecForDeadline(cfg.Deadline)
+		if power, err = dl.AddSectors(store, info.WindowPoStPartitionSectors, sectors, info.SectorSize, quant); err != nil {
+			return big.Zero(), err
+		}
+		if err := deadlines.UpdateDeadline(store, cfg.Deadline, dl); err != nil {
+			return big.Zero(), err
+		}
+		if err := mst.SaveDeadlines(store, deadlines); err != nil {
+			return big.Zero(), err
+		}
+		mst.InitialPledgeRequirement = big.Add(mst.InitialPledgeRequirement, pledge)
+		locked = big.Add(locked, pledge)
+	}
+
+	if err := st.UpdateActorState(miner, &mst); err != nil {
+		return big.Zero(), err
+	}
+
+	var spa power0.State
+	st.ActorState(builtin0.StoragePowerActorAddr, &spa)
+
+	if !power.IsZero() {
+		if err := spa.AddToClaim(store, miner, power.Raw, power.QA); err != nil {
+			return big.Zero(), err
+		}
+	}
+	spa.TotalPledgeCollateral = big.Add(spa.TotalPledgeCollateral, pledge)
+	if !power.IsZero() || !pledge.IsZero() {
+		updateThisEpoch0(&spa)
+	}
+	if err := st.UpdateActorState(builtin0.StoragePowerActorAddr, &spa); err != nil {
+		return big.Zero(), err
+	}
+
+	// enroll the miner for the cron event of its first proving deadline, at
+	// the epoch before the proving period starts, as the constructor does.
+	payload, err := actorsV0{}.ProvingDeadlineCronPayload()
+	if err != nil {
+		return big.Zero(), err
+	}
+	epoch := mst.ProvingPeriodStart - 1
+	return locked, actorsV0{}.EnrollCronEvent(st, miner, epoch, payload)
+}
+
+func (actorsV0) SeedDeal(st *StateTracker, proposal market0.DealProposal, activation abi.ChainEpoch) (abi.DealID, abi.TokenAmount, error) {
+	var (
+		store = st.Stores.ADTStore
+		p     = proposal
+		msa   market0.State
+	)
+	st.ActorState(builtin0.StorageMarketActorAddr, &msa)
+
+	// escrow and lock the balances the deal requires, as publishing does.
+	clientFunds, providerFunds := p.ClientBalanceRequirement(), p.ProviderBalanceRequirement()
+	for _, root := range []*cid.Cid{&msa.EscrowTable, &msa.LockedTable} {
+		table, err := adt0.AsBalanceTable(store, *root)
+		if err != nil {
+			return 0, big.Zero(), err
+		}
+		if err := table.Add(p.Client, clientFunds); err != nil {
+			return 0, big.Zero(), err
+		}
+		if err := table.Add(p.Provider, providerFunds); err != nil {
+			return 0, big.Zero(), err
+		}
+		if *root, err = table.Root(); err != nil {
+			return 0, big.Zero(), err
+		}
+	}
+	msa.TotalClientLockedCollateral = big.Add(msa.TotalClientLockedCollateral, p.ClientCollateral)
+	msa.TotalClientStorageFee = big.Add(msa.TotalClientStorageFee, p.TotalStorageFee())
+	msa.TotalProviderLockedCollateral = big.Add(msa.TotalProviderLockedCollateral, p.ProviderCollateral)
+
+	// publish the deal.
+	id := msa.NextID
+	msa.NextID++
+
+	proposals, err := adt0.AsArray(store, msa.Proposals)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if err := proposals.Set(uint64(id), &p); err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.Proposals, err = proposals.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	pcid, err := p.Cid()
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	pending, err := adt0.AsMap(store, msa.PendingProposals)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if err := pending.Put(abi.CidKey(pcid), &p); err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.PendingProposals, err = pending.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	ops, err := market0.AsSetMultimap(store, msa.DealOpsByEpoch)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if err := ops.Put(p.StartEpoch, id); err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.DealOpsByEpoch, err = ops.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	// activate the deal.
+	states, err := adt0.AsArray(store, msa.States)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	err = states.Set(uint64(id), &market0.DealState{
+		SectorStartEpoch: activation,
+		LastUpdatedEpoch: -1,
+		SlashEpoch:       -1,
+	})
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.States, err = states.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	if err := st.UpdateActorState(builtin0.StorageMarketActorAddr, &msa); err != nil {
+		return 0, big.Zero(), err
+	}
+	return id, big.Add(clientFunds, providerFunds), nil
+}
+
+func (actorsV0) ProvingDeadlineCronPayload() ([]byte, error) {
+	return Serialize(&miner0.CronEventPayload{EventType: miner0.CronEventProvingDeadline})
+}
+
+func (actorsV0) EnrollCronEvent(st *StateTracker, miner address.Address, epoch abi.ChainEpoch, payload []byte) error {
+	var spa power0.State
+	st.ActorState(builtin0.StoragePowerActorAddr, &spa)
+
+	events, err := adt0.AsMultimap(st.Stores.ADTStore, spa.CronEventQueue)
+	if err != nil {
+		return err
+	}
+	err = events.Add(abi.IntKey(int64(epoch)), &power0.CronEvent{
+		MinerAddr:       miner,
+		CallbackPayload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	if spa.CronEventQueue, err = events.Root(); err != nil {
+		return err
+	}
+	if epoch < spa.FirstCronEpoch {
+		spa.FirstCronEpoch = epoch
+	}
+	return st.UpdateActorState(builtin0.StoragePowerActorAddr, &spa)
+}
+
+func (actorsV0) CronEvents(st *StateTracker) ([]CronEvent, error) {
+	var spa power0.State
+	st.ActorState(builtin0.StoragePowerActorAddr, &spa)
+
+	events, err := adt0.AsMultimap(st.Stores.ADTStore, spa.CronEventQueue)
+	if err != nil {
+		return nil, err
+	}
+	var ret []CronEvent
+	err = events.ForAll(func(k string, arr *adt0.Array) error {
+		epoch, err := abi.ParseIntKey(k)
+		if err != nil {
+			return err
+		}
+		var ev power0.CronEvent
+		return arr.ForEach(&ev, func(_ int64) error {
+			ret = append(ret, CronEvent{
+				Epoch:   abi.ChainEpoch(epoch),
+				Miner:   ev.MinerAddr,
+				Payload: ev.CallbackPayload,
+			})
+			return nil
+		})
+	})
+	return ret, err
+}
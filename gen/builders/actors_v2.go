@@ -0,0 +1,679 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+
+	"github.com/chenjianmei111/lotus/chain/actors"
+
+	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+	miner0 "github.com/chenjianmei111/specs-actors/actors/builtin/miner"
+	multisig0 "github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+	paych0 "github.com/chenjianmei111/specs-actors/actors/builtin/paych"
+	power0 "github.com/chenjianmei111/specs-actors/actors/builtin/power"
+	proof0 "github.com/chenjianmei111/specs-actors/actors/runtime/proof"
+	builtin2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin"
+	account2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/account"
+	cron2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/cron"
+	init2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/init"
+	market2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/market"
+	miner2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/miner"
+	multisig2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/multisig"
+	paych2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/paych"
+	power2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/power"
+	reward2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/reward"
+	system2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/system"
+	verifreg2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/verifreg"
+	proof2 "github.com/chenjianmei111/specs-actors/v2/actors/runtime/proof"
+	adt2 "github.com/chenjianmei111/specs-actors/v2/actors/util/adt"
+	smoothing2 "github.com/chenjianmei111/specs-actors/v2/actors/util/smoothing"
+)
+
+func init() {
+	RegisterActorsAdapter(actorsV2{})
+}
+
+// actorsV2 is the ActorsAdapter for specs-actors v2.
+type actorsV2 struct{}
+
+var _ ActorsAdapter = actorsV2{}
+
+func (actorsV2) Version() actors.Version {
+	return actors.Version2
+}
+
+func (actorsV2) Codes() ActorCodes {
+	return ActorCodes{
+		System:           builtin2.SystemActorCodeID,
+		Init:             builtin2.InitActorCodeID,
+		Cron:             builtin2.CronActorCodeID,
+		Account:          builtin2.AccountActorCodeID,
+		Reward:           builtin2.RewardActorCodeID,
+		Power:            builtin2.StoragePowerActorCodeID,
+		Market:           builtin2.StorageMarketActorCodeID,
+		Miner:            builtin2.StorageMinerActorCodeID,
+		Multisig:         builtin2.MultisigActorCodeID,
+		PaymentChannel:   builtin2.PaymentChannelActorCodeID,
+		VerifiedRegistry: builtin2.VerifiedRegistryActorCodeID,
+	}
+}
+
+func (actorsV2) Methods() ActorMethods {
+	var m ActorMethods
+
+	m.Account.Constructor = builtin2.MethodsAccount.Constructor
+	m.Account.PubkeyAddress = builtin2.MethodsAccount.PubkeyAddress
+
+	m.Init.Constructor = builtin2.MethodsInit.Constructor
+	m.Init.Exec = builtin2.MethodsInit.Exec
+
+	m.Cron.Constructor = builtin2.MethodsCron.Constructor
+	m.Cron.EpochTick = builtin2.MethodsCron.EpochTick
+
+	m.Reward.Constructor = builtin2.MethodsReward.Constructor
+	m.Reward.AwardBlockReward = builtin2.MethodsReward.AwardBlockReward
+	m.Reward.ThisEpochReward = builtin2.MethodsReward.ThisEpochReward
+	m.Reward.UpdateNetworkKPI = builtin2.MethodsReward.UpdateNetworkKPI
+
+	m.Multisig.Constructor = builtin2.MethodsMultisig.Constructor
+	m.Multisig.Propose = builtin2.MethodsMultisig.Propose
+	m.Multisig.Approve = builtin2.MethodsMultisig.Approve
+	m.Multisig.Cancel = builtin2.MethodsMultisig.Cancel
+	m.Multisig.AddSigner = builtin2.MethodsMultisig.AddSigner
+	m.Multisig.RemoveSigner = builtin2.MethodsMultisig.RemoveSigner
+	m.Multisig.SwapSigner = builtin2.MethodsMultisig.SwapSigner
+	m.Multisig.ChangeNumApprovalsThreshold = builtin2.MethodsMultisig.ChangeNumApprovalsThreshold
+	m.Multisig.LockBalance = builtin2.MethodsMultisig.LockBalance
+
+	m.Paych.Constructor = builtin2.MethodsPaych.Constructor
+	m.Paych.UpdateChannelState = builtin2.MethodsPaych.UpdateChannelState
+	m.Paych.Settle = builtin2.MethodsPaych.Settle
+	m.Paych.Collect = builtin2.MethodsPaych.Collect
+
+	m.Market.Constructor = builtin2.MethodsMarket.Constructor
+	m.Market.AddBalance = builtin2.MethodsMarket.AddBalance
+	m.Market.WithdrawBalance = builtin2.MethodsMarket.WithdrawBalance
+	m.Market.PublishStorageDeals = builtin2.MethodsMarket.PublishStorageDeals
+	m.Market.VerifyDealsForActivation = builtin2.MethodsMarket.VerifyDealsForActivation
+	m.Market.ActivateDeals = builtin2.MethodsMarket.ActivateDeals
+	m.Market.OnMinerSectorsTerminate = builtin2.MethodsMarket.OnMinerSectorsTerminate
+	m.Market.ComputeDataCommitment = builtin2.MethodsMarket.ComputeDataCommitment
+	m.Market.CronTick = builtin2.MethodsMarket.CronTick
+
+	m.Power.Constructor = builtin2.MethodsPower.Constructor
+	m.Power.CreateMiner = builtin2.MethodsPower.CreateMiner
+	m.Power.UpdateClaimedPower = builtin2.MethodsPower.UpdateClaimedPower
+	m.Power.EnrollCronEvent = builtin2.MethodsPower.EnrollCronEvent
+	m.Power.OnEpochTickEnd = builtin2.MethodsPower.OnEpochTickEnd
+	m.Power.UpdatePledgeTotal = builtin2.MethodsPower.UpdatePledgeTotal
+	// OnConsensusFault was removed in actors v2.
+	m.Power.SubmitPoRepForBulkVerify = builtin2.MethodsPower.SubmitPoRepForBulkVerify
+	m.Power.CurrentTotalPower = builtin2.MethodsPower.CurrentTotalPower
+
+	m.Miner.Constructor = builtin2.MethodsMiner.Constructor
+	m.Miner.ControlAddresses = builtin2.MethodsMiner.ControlAddresses
+	m.Miner.ChangeWorkerAddress = builtin2.MethodsMiner.ChangeWorkerAddress
+	m.Miner.ChangePeerID = builtin2.MethodsMiner.ChangePeerID
+	m.Miner.SubmitWindowedPoSt = builtin2.MethodsMiner.SubmitWindowedPoSt
+	m.Miner.PreCommitSector = builtin2.MethodsMiner.PreCommitSector
+	m.Miner.ProveCommitSector = builtin2.MethodsMiner.ProveCommitSector
+	m.Miner.ExtendSectorExpiration = builtin2.MethodsMiner.ExtendSectorExpiration
+	m.Miner.TerminateSectors = builtin2.MethodsMiner.TerminateSectors
+	m.Miner.DeclareFaults = builtin2.MethodsMiner.DeclareFaults
+	m.Miner.DeclareFaultsRecovered = builtin2.MethodsMiner.DeclareFaultsRecovered
+	m.Miner.OnDeferredCronEvent = builtin2.MethodsMiner.OnDeferredCronEvent
+	m.Miner.CheckSectorProven = builtin2.MethodsMiner.CheckSectorProven
+	// AddLockedFund was replaced by ApplyRewards in actors v2.
+	m.Miner.ApplyRewards = builtin2.MethodsMiner.ApplyRewards
+	m.Miner.ReportConsensusFault = builtin2.MethodsMiner.ReportConsensusFault
+	m.Miner.WithdrawBalance = builtin2.MethodsMiner.WithdrawBalance
+	m.Miner.ConfirmSectorProofsValid = builtin2.MethodsMiner.ConfirmSectorProofsValid
+	m.Miner.ChangeMultiaddrs = builtin2.MethodsMiner.ChangeMultiaddrs
+	m.Miner.CompactPartitions = builtin2.MethodsMiner.CompactPartitions
+	m.Miner.CompactSectorNumbers = builtin2.MethodsMiner.CompactSectorNumbers
+	m.Miner.ConfirmUpdateWorkerKey = builtin2.MethodsMiner.ConfirmUpdateWorkerKey
+	m.Miner.RepayDebt = builtin2.MethodsMiner.RepayDebt
+	m.Miner.ChangeOwnerAddress = builtin2.MethodsMiner.ChangeOwnerAddress
+
+	m.VerifiedRegistry.Constructor = builtin2.MethodsVerifiedRegistry.Constructor
+	m.VerifiedRegistry.AddVerifier = builtin2.MethodsVerifiedRegistry.AddVerifier
+	m.VerifiedRegistry.RemoveVerifier = builtin2.MethodsVerifiedRegistry.RemoveVerifier
+	m.VerifiedRegistry.AddVerifiedClient = builtin2.MethodsVerifiedRegistry.AddVerifiedClient
+	m.VerifiedRegistry.UseBytes = builtin2.MethodsVerifiedRegistry.UseBytes
+	m.VerifiedRegistry.RestoreBytes = builtin2.MethodsVerifiedRegistry.RestoreBytes
+	return m
+}
+
+func (actorsV2) EncodeParams(params cbor.Marshaler) ([]byte, error) {
+	switch p := params.(type) {
+	case *paych0.UpdateChannelStateParams:
+		// actors v2 take no proof; the voucher is encoded as in v0.
+		if len(p.Proof) > 0 {
+			return nil, fmt.Errorf("actors v2 payment channels take no proof")
+		}
+		v2 := &paych2.UpdateChannelStateParams{Secret: p.Secret}
+		if err := Transcode(&p.Sv, &v2.Sv); err != nil {
+			return nil, err
+		}
+		params = v2
+	default:
+		// verify that params supplied as v0 types encode as those of v2.
+		if v2 := paramsV2(params); v2 != nil {
+			if err := Transcode(params, v2); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return Serialize(params)
+}
+
+// paramsV2 returns an empty instance of the v2 params of the methods of the
+// miner, power, market and multisig actors whose params are supplied as the v0
+// types, or nil for other params.
+func paramsV2(params cbor.Marshaler) cbor.Er {
+	switch params.(type) {
+	case *power0.MinerConstructorParams:
+		return new(power2.MinerConstructorParams)
+	case *miner0.ChangeWorkerAddressParams:
+		return new(miner2.ChangeWorkerAddressParams)
+	case *miner0.ChangePeerIDParams:
+		return new(miner2.ChangePeerIDParams)
+	case *miner0.SectorPreCommitInfo:
+		return new(miner2.SectorPreCommitInfo)
+	case *miner0.ProveCommitSectorParams:
+		return new(miner2.ProveCommitSectorParams)
+	case *miner0.ExtendSectorExpirationParams:
+		return new(miner2.ExtendSectorExpirationParams)
+	case *miner0.TerminateSectorsParams:
+		return new(miner2.TerminateSectorsParams)
+	case *miner0.DeclareFaultsParams:
+		return new(miner2.DeclareFaultsParams)
+	case *miner0.DeclareFaultsRecoveredParams:
+		return new(miner2.DeclareFaultsRecoveredParams)
+	case *miner0.CronEventPayload:
+		return new(miner2.CronEventPayload)
+	case *miner0.CheckSectorProvenParams:
+		return new(miner2.CheckSectorProvenParams)
+	case *miner0.ReportConsensusFaultParams:
+		return new(miner2.ReportConsensusFaultParams)
+	case *miner0.WithdrawBalanceParams:
+		return new(miner2.WithdrawBalanceParams)
+	case *builtin0.ConfirmSectorProofsParams:
+		return new(builtin2.ConfirmSectorProofsParams)
+	case *miner0.ChangeMultiaddrsParams:
+		return new(miner2.ChangeMultiaddrsParams)
+	case *miner0.CompactPartitionsParams:
+		return new(miner2.CompactPartitionsParams)
+	case *miner0.CompactSectorNumbersParams:
+		return new(miner2.CompactSectorNumbersParams)
+	case *power0.CreateMinerParams:
+		return new(power2.CreateMinerParams)
+	case *power0.UpdateClaimedPowerParams:
+		return new(power2.UpdateClaimedPowerParams)
+	case *power0.EnrollCronEventParams:
+		return new(power2.EnrollCronEventParams)
+	case *proof0.SealVerifyInfo:
+		return new(proof2.SealVerifyInfo)
+	case *market0.WithdrawBalanceParams:
+		return new(market2.WithdrawBalanceParams)
+	case *market0.PublishStorageDealsParams:
+		return new(market2.PublishStorageDealsParams)
+	case *market0.VerifyDealsForActivationParams:
+		return new(market2.VerifyDealsForActivationParams)
+	case *market0.ActivateDealsParams:
+		return new(market2.ActivateDealsParams)
+	case *market0.OnMinerSectorsTerminateParams:
+		return new(market2.OnMinerSectorsTerminateParams)
+	case *market0.ComputeDataCommitmentParams:
+		return new(market2.ComputeDataCommitmentParams)
+	case *multisig0.ProposeParams:
+		return new(multisig2.ProposeParams)
+	case *multisig0.TxnIDParams:
+		return new(multisig2.TxnIDParams)
+	case *multisig0.AddSignerParams:
+		return new(multisig2.AddSignerParams)
+	case *multisig0.RemoveSignerParams:
+		return new(multisig2.RemoveSignerParams)
+	case *multisig0.SwapSignerParams:
+		return new(multisig2.SwapSignerParams)
+	case *multisig0.ChangeNumApprovalsThresholdParams:
+		return new(multisig2.ChangeNumApprovalsThresholdParams)
+	}
+	return nil
+}
+
+func (actorsV2) NewState(code cid.Cid) cbor.Unmarshaler {
+	switch code {
+	case builtin2.AccountActorCodeID:
+		return new(account2.State)
+	case builtin2.CronActorCodeID:
+		return new(cron2.State)
+	case builtin2.InitActorCodeID:
+		return new(init2.State)
+	case builtin2.StorageMarketActorCodeID:
+		return new(market2.State)
+	case builtin2.StorageMinerActorCodeID:
+		return new(miner2.State)
+	case builtin2.MultisigActorCodeID:
+		return new(multisig2.State)
+	case builtin2.PaymentChannelActorCodeID:
+		return new(paych2.State)
+	case builtin2.StoragePowerActorCodeID:
+		return new(power2.State)
+	case builtin2.RewardActorCodeID:
+		return new(reward2.State)
+	case builtin2.VerifiedRegistryActorCodeID:
+		return new(verifreg2.State)
+	}
+	return nil
+}
+
+func (actorsV2) EmptyMultimap(st *StateTracker) (cid.Cid, error) {
+	return adt2.MakeEmptyMultimap(st.Stores.ADTStore).Root()
+}
+
+func (actorsV2) EmptyDeadline(emptyArray cid.Cid) cbor.Marshaler {
+	return miner2.ConstructDeadline(emptyArray)
+}
+
+func (actorsV2) EmptyVestingFunds() cbor.Marshaler {
+	return miner2.ConstructVestingFunds()
+}
+
+func (actorsV2) ZeroState(st *StateTracker) []ActorState {
+	return []ActorState{{
+		Addr:    builtin2.InitActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.InitActorCodeID,
+		State:   init2.ConstructState(st.EmptyMapCid, "chain-validation"),
+	}, {
+		Addr:    builtin2.RewardActorAddr,
+		Balance: TotalNetworkBalance,
+		Code:    builtin2.RewardActorCodeID,
+		State:   reward2.ConstructState(big.Zero()),
+	}, {
+		Addr:    builtin2.BurntFundsActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.AccountActorCodeID,
+		State:   &account2.State{Address: builtin2.BurntFundsActorAddr},
+	}, {
+		Addr:    builtin2.StoragePowerActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.StoragePowerActorCodeID,
+		State:   power2.ConstructState(st.EmptyMapCid, st.EmptyMultiMapCid),
+	}, {
+		Addr:    builtin2.StorageMarketActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.StorageMarketActorCodeID,
+		State:   market2.ConstructState(st.EmptyArrayCid, st.EmptyMapCid, st.EmptyMultiMapCid),
+	}, {
+		Addr:    builtin2.SystemActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.SystemActorCodeID,
+		State:   new(system2.State),
+	}, {
+		Addr:    builtin2.CronActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.CronActorCodeID,
+		State:   cron2.ConstructState(cron2.BuiltInEntries()),
+	}, {
+		Addr:    builtin2.VerifiedRegistryActorAddr,
+		Balance: big.Zero(),
+		Code:    builtin2.VerifiedRegistryActorCodeID,
+		State:   verifreg2.ConstructState(st.EmptyMapCid, RootVerifier),
+	}}
+}
+
+func (actorsV2) AccountState(addr address.Address) cbor.Marshaler {
+	return &account2.State{Address: addr}
+}
+
+func (actorsV2) MinerState(st *StateTracker, owner, worker address.Address, cfg MinerActorCfg) (cbor.Marshaler, error) {
+	info, err := miner2.ConstructMinerInfo(owner, worker, nil, cfg.PeerID, cfg.Multiaddrs, cfg.SealProofType)
+	if err != nil {
+		return nil, err
+	}
+	infoCid, err := st.Stores.CBORStore.Put(context.Background(), info)
+	if err != nil {
+		return nil, err
+	}
+	return miner2.ConstructState(infoCid,
+		cfg.PeriodBoundary,
+		0, // deadline index.
+		st.EmptyBitfieldCid,
+		st.EmptyArrayCid,
+		st.EmptyMapCid,
+		st.EmptyDeadlinesCid,
+		st.EmptyVestingFundsCid,
+	)
+}
+
+func (actorsV2) RegisterMiner(st *StateTracker, miner address.Address, cfg MinerActorCfg) error {
+	var spa power2.State
+	st.ActorState(builtin2.StoragePowerActorAddr, &spa)
+
+	claims, err := adt2.AsMap(st.Stores.ADTStore, spa.Claims)
+	if err != nil {
+		return err
+	}
+	err = claims.Put(abi.AddrKey(miner), &power2.Claim{
+		RawBytePower:    abi.NewStoragePower(0),
+		QualityAdjPower: abi.NewStoragePower(0),
+	})
+	if err != nil {
+		return err
+	}
+	if spa.Claims, err = claims.Root(); err != nil {
+		return err
+	}
+	spa.MinerCount += 1
+
+	// claim the initial power through the accounting of the actor itself,
+	// which maintains the totals and the count of miners above the consensus
+	// minimum power.
+	if !cfg.RawPower.IsZero() || !cfg.QAPower.IsZero() {
+		if err := spa.AddToClaim(st.Stores.ADTStore, miner, cfg.RawPower, cfg.QAPower); err != nil {
+			return err
+		}
+		updateThisEpoch2(&spa)
+	}
+
+	return st.UpdateActorState(builtin2.StoragePowerActorAddr, &spa)
+}
+
+// updateThisEpoch2 brings the values of the storage power actor state for
+// the current epoch in line with its totals, as its cron tick would have at the
+// end of the previous epoch: the raw byte and quality-adjusted power, the
+// smoothed estimate of the quality-adjusted power, and the pledge collateral.
+// The estimate is reset to the current quality-adjusted power, with no
+// velocity, as there's no history to filter.
+func updateThisEpoch2(spa *power2.State) {
+	spa.ThisEpochRawBytePower, spa.ThisEpochQualityAdjPower = power2.CurrentTotalPower(spa)
+	spa.ThisEpochQAPowerSmoothed = smoothing2.NewEstimate(spa.ThisEpochQualityAdjPower, big.Zero())
+	spa.ThisEpochPledgeCollateral = spa.TotalPledgeCollateral
+}
+
+func (actorsV2) SeedSectors(st *StateTracker, miner address.Address, cfg MinerSectorsCfg) (abi.TokenAmount, error) {
+	var (
+		store  = st.Stores.ADTStore
+		locked = big.Zero()
+		pledge = big.Zero()
+		mst    miner2.State
+	)
+	st.ActorState(miner, &mst)
+
+	info, err := mst.GetInfo(store)
+	if err != nil {
+		return big.Zero(), err
+	}
+
+	for _, s := range cfg.PreCommitted {
+		sealed, err := fakeSealedCID(miner2.SealedCIDPrefix, miner, s.Number)
+		if err != nil {
+			return big.Zero(), err
+		}
+		if err := mst.AllocateSectorNumber(store, s.Number); err != nil {
+			return big.Zero(), err
+		}
+		err = mst.PutPrecommittedSector(store, &miner2.SectorPreCommitOnChainInfo{
+			Info: miner2.SectorPreCommitInfo{
+				SealProof:     info.SealProofType,
+				SectorNumber:  s.Number,
+				SealedCID:     sealed,
+				SealRandEpoch: s.Epoch - 1,
+				DealIDs:       s.DealIDs,
+				Expiration:    s.Expiration,
+			},
+			PreCommitDeposit:   s.Collateral,
+			PreCommitEpoch:     s.Epoch,
+			DealWeight:         s.DealWeight,
+			VerifiedDealWeight: s.VerifiedDealWeight,
+		})
+		if err != nil {
+			return big.Zero(), err
+		}
+		mst.PreCommitDeposits = big.Add(mst.PreCommitDeposits, s.Collateral)
+		locked = big.Add(locked, s.Collateral)
+	}
+
+	sectors := make([]*miner2.SectorOnChainInfo, 0, len(cfg.Active))
+	for _, s := range cfg.Active {
+		sealed, err := fakeSealedCID(miner2.SealedCIDPrefix, miner, s.Number)
+		if err != nil {
+			return big.Zero(), err
+		}
+		if err := mst.AllocateSectorNumber(store, s.Number); err != nil {
+			return big.Zero(), err
+		}
+		sectors = append(sectors, &miner2.SectorOnChainInfo{
+			SectorNumber:          s.Number,
+			SealProof:             info.SealProofType,
+			SealedCID:             sealed,
+			DealIDs:               s.DealIDs,
+			Activation:            s.Epoch,
+			Expiration:            s.Expiration,
+			DealWeight:            s.DealWeight,
+			VerifiedDealWeight:    s.VerifiedDealWeight,
+			InitialPledge:         s.Collateral,
+			ExpectedDayReward:     big.Zero(),
+			ExpectedStoragePledge: big.Zero(),
+			ReplacedSectorAge:     0,
+			ReplacedDayReward:     big.Zero(),
+		})
+		pledge = big.Add(pledge, s.Collateral)
+	}
+
+	power := miner2.NewPowerPairZero()
+	if len(sectors) > 0 {
+		if err := mst.PutSectors(store, sectors...); err != nil {
+			return big.Zero(), err
+		}
+		deadlines, err := mst.LoadDeadlines(store)
+		if err != nil {
+			return big.Zero(), err
+		}
+		dl, err := deadlines.LoadDeadline(store, cfg.Deadline)
+		if err != nil {
+			return big.Zero(), err
+		}
+		// the sectors are added as proven, so that their power is active.
+		quant := mst.QuantSpecForDeadline(cfg.Deadline)
+		if power, err = dl.AddSectors(store, info.WindowPoStPartitionSectors, true, sectors, info.SectorSize, quant); err != nil {
+			return big.Zero(), err
+		}
+		if err := deadlines.UpdateDeadline(store, cfg.Deadline, dl); err != nil {
+			return big.Zero(), err
+		}
+		if err := mst.SaveDeadlines(store, deadlines); err != nil {
+			return big.Zero(), err
+		}
+		mst.InitialPledge = big.Add(mst.InitialPledge, pledge)
+		locked = big.Add(locked, pledge)
+	}
+
+	if err := st.UpdateActorState(miner, &mst); err != nil {
+		return big.Zero(), err
+	}
+
+	var spa power2.State
+	st.ActorState(builtin2.StoragePowerActorAddr, &spa)
+
+	if !power.IsZero() {
+		if err := spa.AddToClaim(store, miner, power.Raw, power.QA); err != nil {
+			return big.Zero(), err
+		}
+	}
+	spa.TotalPledgeCollateral = big.Add(spa.TotalPledgeCollateral, pledge)
+	if !power.IsZero() || !pledge.IsZero() {
+		updateThisEpoch2(&spa)
+	}
+	if err := st.UpdateActorState(builtin2.StoragePowerActorAddr, &spa); err != nil {
+		return big.Zero(), err
+	}
+
+	// enroll the miner for the cron event of its first proving deadline.
+	payload, err := actorsV2{}.ProvingDeadlineCronPayload()
+	if err != nil {
+		return big.Zero(), err
+	}
+	epoch := mst.DeadlineInfo(mst.ProvingPeriodStart).Last()
+	return locked, actorsV2{}.EnrollCronEvent(st, miner, epoch, payload)
+}
+
+func (actorsV2) SeedDeal(st *StateTracker, proposal market0.DealProposal, activation abi.ChainEpoch) (abi.DealID, abi.TokenAmount, error) {
+	var (
+		store = st.Stores.ADTStore
+		p     market2.DealProposal
+		msa   market2.State
+	)
+	if err := Transcode(&proposal, &p); err != nil {
+		return 0, big.Zero(), err
+	}
+	st.ActorState(builtin2.StorageMarketActorAddr, &msa)
+
+	// escrow and lock the balances the deal requires, as publishing does.
+	clientFunds, providerFunds := p.ClientBalanceRequirement(), p.ProviderBalanceRequirement()
+	for _, root := range []*cid.Cid{&msa.EscrowTable, &msa.LockedTable} {
+		table, err := adt2.AsBalanceTable(store, *root)
+		if err != nil {
+			return 0, big.Zero(), err
+		}
+		if err := table.Add(p.Client, clientFunds); err != nil {
+			return 0, big.Zero(), err
+		}
+		if err := table.Add(p.Provider, providerFunds); err != nil {
+			return 0, big.Zero(), err
+		}
+		if *root, err = table.Root(); err != nil {
+			return 0, big.Zero(), err
+		}
+	}
+	msa.TotalClientLockedCollateral = big.Add(msa.TotalClientLockedCollateral, p.ClientCollateral)
+	msa.TotalClientStorageFee = big.Add(msa.TotalClientStorageFee, p.TotalStorageFee())
+	msa.TotalProviderLockedCollateral = big.Add(msa.TotalProviderLockedCollateral, p.ProviderCollateral)
+
+	// publish the deal.
+	id := msa.NextID
+	msa.NextID++
+
+	proposals, err := adt2.AsArray(store, msa.Proposals)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if err := proposals.Set(uint64(id), &p); err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.Proposals, err = proposals.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	pcid, err := p.Cid()
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	pending, err := adt2.AsMap(store, msa.PendingProposals)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if err := pending.Put(abi.CidKey(pcid), &p); err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.PendingProposals, err = pending.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	ops, err := market2.AsSetMultimap(store, msa.DealOpsByEpoch)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if err := ops.Put(p.StartEpoch, id); err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.DealOpsByEpoch, err = ops.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	// activate the deal.
+	states, err := adt2.AsArray(store, msa.States)
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	err = states.Set(uint64(id), &market2.DealState{
+		SectorStartEpoch: activation,
+		LastUpdatedEpoch: -1,
+		SlashEpoch:       -1,
+	})
+	if err != nil {
+		return 0, big.Zero(), err
+	}
+	if msa.States, err = states.Root(); err != nil {
+		return 0, big.Zero(), err
+	}
+
+	if err := st.UpdateActorState(builtin2.StorageMarketActorAddr, &msa); err != nil {
+		return 0, big.Zero(), err
+	}
+	return id, big.Add(clientFunds, providerFunds), nil
+}
+
+func (actorsV2) ProvingDeadlineCronPayload() ([]byte, error) {
+	return Serialize(&miner2.CronEventPayload{EventType: miner2.CronEventProvingDeadline})
+}
+
+func (actorsV2) EnrollCronEvent(st *StateTracker, miner address.Address, epoch abi.ChainEpoch, payload []byte) error {
+	var spa power2.State
+	st.ActorState(builtin2.StoragePowerActorAddr, &spa)
+
+	events, err := adt2.AsMultimap(st.Stores.ADTStore, spa.CronEventQueue)
+	if err != nil {
+		return err
+	}
+	err = events.Add(abi.IntKey(int64(epoch)), &power2.CronEvent{
+		MinerAddr:       miner,
+		CallbackPayload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	if spa.CronEventQueue, err = events.Root(); err != nil {
+		return err
+	}
+	if epoch < spa.FirstCronEpoch {
+		spa.FirstCronEpoch = epoch
+	}
+	return st.UpdateActorState(builtin2.StoragePowerActorAddr, &spa)
+}
+
+func (actorsV2) CronEvents(st *StateTracker) ([]CronEvent, error) {
+	var spa power2.State
+	st.ActorState(builtin2.StoragePowerActorAddr, &spa)
+
+	events, err := adt2.AsMultimap(st.Stores.ADTStore, spa.CronEventQueue)
+	if err != nil {
+		return nil, err
+	}
+	var ret []CronEvent
+	err = events.ForAll(func(k string, arr *adt2.Array) error {
+		epoch, err := abi.ParseIntKey(k)
+		if err != nil {
+			return err
+		}
+		var ev power2.CronEvent
+		return arr.ForEach(&ev, func(_ int64) error {
+			ret = append(ret, CronEvent{
+				Epoch:   abi.ChainEpoch(epoch),
+				Miner:   ev.MinerAddr,
+				Payload: ev.CallbackPayload,
+			})
+			return nil
+		})
+	})
+	return ret, err
+}
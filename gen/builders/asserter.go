@@ -2,7 +2,7 @@ package builders
 
 import (
 	"fmt"
-	"os"
+	"runtime"
 
 	"github.com/chenjianmei111/go-address"
 	"github.com/chenjianmei111/go-state-types/abi"
@@ -31,6 +31,18 @@ type Asserter struct {
 	// suppliers contains functions that the Asserter uses to obtain values from
 	// the builder that may vary during construction.
 	suppliers suppliers
+
+	// failure records the last assertion failure. It is shared with forks of
+	// this Asserter.
+	failure *AssertionFailure
+}
+
+// AssertionFailure describes a failed assertion.
+type AssertionFailure struct {
+	// Stage is the builder stage the assertion failed at.
+	Stage Stage
+	// Message is the assertion failure message.
+	Message string
 }
 
 // suppliers is a struct containing functions that the Asserter will use to
@@ -45,7 +57,7 @@ type suppliers struct {
 var _ require.TestingT = &Asserter{}
 
 func NewAsserter(id string, pv ProtocolVersion, lenient bool, suppliers suppliers) *Asserter {
-	a := &Asserter{id: id, pv: pv, lenient: lenient, suppliers: suppliers}
+	a := &Asserter{id: id, pv: pv, lenient: lenient, suppliers: suppliers, failure: new(AssertionFailure)}
 	a.Assertions = require.New(a)
 	return a
 }
@@ -195,14 +207,23 @@ func (a *Asserter) EveryMessageSenderSatisfies(predicate ActorPredicate, except
 	a.MessageSendersSatisfy(predicate, ams...)
 }
 
+// Failure returns the last assertion failure, if any.
+func (a *Asserter) Failure() (AssertionFailure, bool) {
+	return *a.failure, a.failure.Message != ""
+}
+
+// FailNow aborts the generation of this vector, unless we're in lenient mode.
+// It terminates the calling goroutine via runtime.Goexit, so it must be called
+// from the goroutine the vector is being generated in.
 func (a *Asserter) FailNow() {
 	if !a.lenient {
-		os.Exit(1)
+		runtime.Goexit()
 	}
 	fmt.Println("⏩  ignoring assertion failure in lenient mode")
 }
 
 func (a *Asserter) Errorf(format string, args ...interface{}) {
 	stage := a.stage
+	*a.failure = AssertionFailure{Stage: stage, Message: fmt.Sprintf(format, args...)}
 	fmt.Printf("❌  id: %s, pv: %s, stage: %s:"+format, append([]interface{}{a.id, a.pv.ID, stage}, args...)...)
 }
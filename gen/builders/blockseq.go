@@ -0,0 +1,166 @@
+package builders
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/conformance"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// BlockSeq is a sequence of blocks arriving at the driver during the test.
+// BlockSeq provides methods to open new epochs, and to enroll blocks in them,
+// along with the outcome the driver is expected to reach for each block.
+type BlockSeq struct {
+	epochs      []*BlockEpoch
+	epochOffset abi.ChainEpoch
+
+	// msgIdx is an index that stores unique messages enlisted in accepted
+	// blocks in this sequence.
+	msgIdx map[cid.Cid]*ApplicableMessage
+
+	// orderedMsgs keeps track of the order of messages as they appear when
+	// new accepted blocks are added.
+	orderedMsgs []*ApplicableMessage
+}
+
+// BlockEpoch groups the blocks arriving at a given epoch. Accepted blocks in
+// a BlockEpoch form the tipset that will be executed at that epoch.
+type BlockEpoch struct {
+	bs *BlockSeq
+
+	EpochOffset abi.ChainEpoch
+	BaseFee     abi.TokenAmount
+
+	// PostStateRoot stores the state root CID after executing the accepted
+	// blocks of this epoch. It can be used with Asserter#AtState to obtain an
+	// asserter against that state root. It remains undefined if all blocks
+	// in this epoch were rejected.
+	PostStateRoot cid.Cid
+
+	Blocks []*SeqBlock
+}
+
+// SeqBlock is a block enrolled in a BlockSeq.
+type SeqBlock struct {
+	Miner    Miner
+	WinCount int64
+	Messages []*ApplicableMessage
+
+	// Expect is the outcome the driver is expected to reach when validating
+	// this block. It defaults to schema.BlockAccept.
+	Expect schema.BlockOutcome
+	// Reason explains why a block is expected to be rejected.
+	Reason string
+
+	// Header is the block header, populated when applies are committed.
+	Header *types.BlockHeader
+
+	// tamperFns are applied to the header before it is signed.
+	tamperFns []func(h *types.BlockHeader)
+	// tamperSignedFns are applied to the header after it is signed.
+	tamperSignedFns []func(h *types.BlockHeader)
+}
+
+// NewBlockSeq returns a new BlockSeq object initialized at the provided
+// epoch.
+func NewBlockSeq(initialEpoch abi.ChainEpoch) *BlockSeq {
+	return &BlockSeq{
+		epochOffset: initialEpoch,
+		msgIdx:      make(map[cid.Cid]*ApplicableMessage),
+	}
+}
+
+// All returns all epochs that have been registered.
+func (bs *BlockSeq) All() []*BlockEpoch {
+	return bs.epochs
+}
+
+// Messages returns all ApplicableMessages that have been included in accepted
+// blocks, ordered based on inclusion.
+func (bs *BlockSeq) Messages() []*ApplicableMessage {
+	msgs := make([]*ApplicableMessage, len(bs.orderedMsgs))
+	copy(msgs, bs.orderedMsgs)
+	return msgs
+}
+
+// Next opens a new epoch, with the supplied base fee, and advances the epoch
+// by 1. A zero-valued base fee is replaced by the driver's default.
+func (bs *BlockSeq) Next(baseFee abi.TokenAmount) *BlockEpoch {
+	be := &BlockEpoch{
+		bs:          bs,
+		EpochOffset: bs.epochOffset,
+		BaseFee:     conformance.BaseFeeOrDefault(baseFee.Int),
+	}
+	bs.epochs = append(bs.epochs, be)
+	bs.epochOffset++ // advance the epoch.
+	return be
+}
+
+// NullRounds enrols as many null rounds as indicated, advancing the epoch by
+// the count.
+func (bs *BlockSeq) NullRounds(count uint64) {
+	bs.epochOffset += abi.ChainEpoch(count)
+}
+
+// Block adds a new block arriving at this epoch, produced by the indicated
+// miner, with the supplied wincount, and containing the listed (and
+// previously staged) messages, which must be signed. The block is expected to
+// be accepted; use SeqBlock#ExpectReject to change that.
+func (be *BlockEpoch) Block(miner Miner, winCount int64, msgs ...*ApplicableMessage) *SeqBlock {
+	b := &SeqBlock{
+		Miner:    miner,
+		WinCount: winCount,
+		Messages: msgs,
+		Expect:   schema.BlockAccept,
+	}
+	be.Blocks = append(be.Blocks, b)
+	return b
+}
+
+// Accepted returns the blocks in this epoch that are expected to be accepted.
+func (be *BlockEpoch) Accepted() []*SeqBlock {
+	var ret []*SeqBlock
+	for _, b := range be.Blocks {
+		if b.Expect == schema.BlockAccept {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+// ExpectReject marks this block as one that the driver must reject, for the
+// supplied reason.
+func (b *SeqBlock) ExpectReject(reason string) *SeqBlock {
+	b.Expect = schema.BlockReject
+	b.Reason = reason
+	return b
+}
+
+// Tamper registers a function that mutates the block header after all
+// fields have been derived by the builder, but before it is signed.
+func (b *SeqBlock) Tamper(fn func(h *types.BlockHeader)) *SeqBlock {
+	b.tamperFns = append(b.tamperFns, fn)
+	return b
+}
+
+// TamperSigned registers a function that mutates the block header after it
+// has been signed, e.g. to corrupt the signature.
+func (b *SeqBlock) TamperSigned(fn func(h *types.BlockHeader)) *SeqBlock {
+	b.tamperSignedFns = append(b.tamperSignedFns, fn)
+	return b
+}
+
+// recordMessages indexes the messages of an accepted block, supplied in
+// application order.
+func (bs *BlockSeq) recordMessages(msgs []*ApplicableMessage) {
+	for _, am := range msgs {
+		// if we see this message for the first time, add it to the `msgIdx` map and to the `orderMsgs` slice.
+		if _, ok := bs.msgIdx[am.Message.Cid()]; !ok {
+			bs.msgIdx[am.Message.Cid()] = am
+			bs.orderedMsgs = append(bs.orderedMsgs, am)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package builders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/miner"
+	"github.com/chenjianmei111/lotus/chain/state"
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/chain/vm"
+	"github.com/chenjianmei111/lotus/lib/blockstore"
+	"github.com/chenjianmei111/lotus/lib/sigs"
+
+	"github.com/chenjianmei111/specs-actors/actors/util/adt"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// BlockSeqHead is the head of the chain that the blocks of a blockseq-class
+// vector extend: the last tipset executed, or the precondition state if no
+// tipset has been executed yet.
+type BlockSeqHead struct {
+	// Parents are the CIDs of the blocks in the head tipset.
+	Parents []cid.Cid
+	// StateRoot is the state root resulting from executing the head tipset.
+	StateRoot cid.Cid
+	// Receipts is the receipts root of the head tipset.
+	Receipts cid.Cid
+}
+
+// NewBlockSeqHead returns the head of a chain with no tipsets executed on top
+// of the precondition state root; its receipts root is an empty AMT.
+func NewBlockSeqHead(bs blockstore.Blockstore, preroot cid.Cid) (BlockSeqHead, error) {
+	store := adt.WrapStore(context.Background(), cbor.NewCborStore(bs))
+	receipts, err := adt.MakeEmptyArray(store).Root()
+	if err != nil {
+		return BlockSeqHead{}, fmt.Errorf("failed to create empty receipts root: %w", err)
+	}
+	return BlockSeqHead{StateRoot: preroot, Receipts: receipts}, nil
+}
+
+// ValidateBlock validates the supplied block, arriving at the supplied epoch,
+// against this head, and returns its decoded header. The block is valid if
+// its header extends this head (height, parents, parent state and receipts
+// roots), is signed by the worker of its miner, and commits to the messages
+// of the block, whose signatures must be valid.
+//
+// Consensus rules that the conformance driver can't check (tickets, election
+// and PoSt proofs, beacon entries, timestamps) are not validated.
+func (h BlockSeqHead) ValidateBlock(bs blockstore.Blockstore, epoch abi.ChainEpoch, ab schema.ApplyBlock) (*types.BlockHeader, error) {
+	header, err := types.DecodeBlock(ab.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block header: %w", err)
+	}
+
+	switch {
+	case header.Height != epoch:
+		return header, fmt.Errorf("wrong height; expected %d, got %d", epoch, header.Height)
+	case !cidsEqual(header.Parents, h.Parents):
+		return header, fmt.Errorf("wrong parents; expected %v, got %v", h.Parents, header.Parents)
+	case header.ParentStateRoot != h.StateRoot:
+		return header, fmt.Errorf("wrong parent state root; expected %s, got %s", h.StateRoot, header.ParentStateRoot)
+	case header.ParentMessageReceipts != h.Receipts:
+		return header, fmt.Errorf("wrong parent receipts root; expected %s, got %s", h.Receipts, header.ParentMessageReceipts)
+	case header.BlockSig == nil:
+		return header, fmt.Errorf("missing block signature")
+	}
+
+	cst := cbor.NewCborStore(bs)
+	tree, err := state.LoadStateTree(cst, h.StateRoot)
+	if err != nil {
+		return header, fmt.Errorf("failed to load state tree: %w", err)
+	}
+
+	worker, err := minerWorker(tree, cst, header)
+	if err != nil {
+		return header, err
+	}
+	sb, err := header.SigningBytes()
+	if err != nil {
+		return header, fmt.Errorf("failed to obtain signing bytes: %w", err)
+	}
+	if err := sigs.Verify(header.BlockSig, worker, sb); err != nil {
+		return header, fmt.Errorf("invalid block signature: %w", err)
+	}
+
+	blsCids, secpCids, err := messageCids(ab)
+	if err != nil {
+		return header, err
+	}
+	store := adt.WrapStore(context.Background(), cst)
+	meta, err := storeMsgMeta(store, blsCids, secpCids)
+	if err != nil {
+		return header, err
+	}
+	if header.Messages != meta {
+		return header, fmt.Errorf("wrong messages root; expected %s, got %s", meta, header.Messages)
+	}
+
+	blk := schema.Block{BLSMessages: ab.BLSMessages, SECPMessages: ab.SECPMessages}
+	if header.BLSAggregate != nil {
+		blk.BLSAggregate = &schema.Signature{Type: schema.SignatureType(header.BLSAggregate.Type), Data: header.BLSAggregate.Data}
+	}
+	if _, err := verifyBlockMessages(tree, cst, blk); err != nil {
+		return header, err
+	}
+	return header, nil
+}
+
+// minerWorker returns the key address of the worker of the miner that
+// produced the supplied block.
+func minerWorker(tree *state.StateTree, cst cbor.IpldStore, header *types.BlockHeader) (address.Address, error) {
+	act, err := tree.GetActor(header.Miner)
+	if err != nil {
+		return address.Undef, fmt.Errorf("failed to load miner actor %s: %w", header.Miner, err)
+	}
+	mas, err := miner.Load(adt.WrapStore(context.Background(), cst), act)
+	if err != nil {
+		return address.Undef, fmt.Errorf("failed to load state of miner %s: %w", header.Miner, err)
+	}
+	info, err := mas.Info()
+	if err != nil {
+		return address.Undef, fmt.Errorf("failed to load info of miner %s: %w", header.Miner, err)
+	}
+	worker, err := vm.ResolveToKeyAddr(tree, cst, info.Worker)
+	if err != nil {
+		return address.Undef, fmt.Errorf("failed to resolve the key address of worker %s: %w", info.Worker, err)
+	}
+	return worker, nil
+}
+
+// messageCids returns the CIDs the block header commits to for the messages
+// of the supplied block: those of the bare BLS messages, and those of the
+// secp256k1 SignedMessages.
+func messageCids(ab schema.ApplyBlock) (bls, secp []cid.Cid, err error) {
+	for i, b := range ab.BLSMessages {
+		msg, err := types.DecodeMessage(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode BLS message %d: %w", i, err)
+		}
+		bls = append(bls, msg.Cid())
+	}
+	for i, b := range ab.SECPMessages {
+		smsg, err := types.DecodeSignedMessage(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode secp256k1 message %d: %w", i, err)
+		}
+		secp = append(secp, smsg.Cid())
+	}
+	return bls, secp, nil
+}
+
+// storeMsgMeta stores the MsgMeta object referencing the supplied BLS and
+// secp256k1 message CIDs, along with its AMTs, and returns its CID.
+func storeMsgMeta(store adt.Store, bls, secp []cid.Cid) (cid.Cid, error) {
+	blsRoot, err := storeCidAMT(store, bls)
+	if err != nil {
+		return cid.Undef, err
+	}
+	secpRoot, err := storeCidAMT(store, secp)
+	if err != nil {
+		return cid.Undef, err
+	}
+	meta := &types.MsgMeta{
+		BlsMessages:   blsRoot,
+		SecpkMessages: secpRoot,
+	}
+	c, err := store.Put(store.Context(), meta)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to store message meta: %w", err)
+	}
+	return c, nil
+}
+
+// storeCidAMT stores an AMT holding the supplied CIDs, and returns its root.
+func storeCidAMT(store adt.Store, cids []cid.Cid) (cid.Cid, error) {
+	arr := adt.MakeEmptyArray(store)
+	for i, c := range cids {
+		c := cbg.CborCid(c)
+		if err := arr.Set(uint64(i), &c); err != nil {
+			return cid.Undef, fmt.Errorf("failed to add message to block messages AMT: %w", err)
+		}
+	}
+	return arr.Root()
+}
+
+func cidsEqual(a, b []cid.Cid) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
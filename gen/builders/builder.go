@@ -59,6 +59,10 @@ type BuilderCommon struct {
 	Assert *Asserter
 	Wallet *Wallet
 
+	// Randomness is the randomness source served to the VM. Use it to declare
+	// randomness rules, or to record the randomness requested by the VM.
+	Randomness *Randomness
+
 	// ProtocolVersion this vector is being built against.
 	ProtocolVersion ProtocolVersion
 }
@@ -0,0 +1,434 @@
+package builders
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/crypto"
+	"github.com/ipfs/go-cid"
+	"github.com/minio/blake2b-simd"
+
+	"github.com/chenjianmei111/lotus/build"
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/conformance"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// BlockSeqVectorBuilder builds a blockseq-class vector. It follows the same
+// staged approach as TipsetVectorBuilder.
+//
+// During the precondition stage, the user sets up the pre-existing state of
+// the system, including the miners that are going to be producing the blocks
+// comprising the vector. If the initial epoch is different to 0, the user must
+// also set it during the precondition stage via SetInitialEpochOffset.
+//
+// During the application stage, the user stages the messages they later want
+// to incorporate to a block in the StagedMessages object, and registers the
+// blocks arriving at each epoch in the Blocks object, by calling
+// BlockSeq#Next and BlockEpoch#Block. Every block is expected to be accepted
+// by default; blocks can be tampered with, and flagged as expected to be
+// rejected via SeqBlock#ExpectReject.
+//
+// Staged messages are signed by default, and must remain so: blocks carry BLS
+// messages bare, with their signatures aggregated in the header, and
+// secp256k1 messages as SignedMessages.
+//
+// When committing applies, the builder derives the full block headers
+// (parents, state roots, message roots, BLS aggregates, timestamps, tickets,
+// election proofs), signs them with the miner's worker key, and executes the
+// accepted blocks of every epoch as a tipset.
+type BlockSeqVectorBuilder struct {
+	*BuilderCommon
+
+	// StagedMessages is a staging area for messages.
+	StagedMessages *Messages
+	// StateTracker is used for staging messages, and it's scrapped and replaced
+	// by a fork at the PreRoot when committing applies.
+	StateTracker *StateTracker
+
+	InitialEpochOffset abi.ChainEpoch
+
+	Blocks *BlockSeq
+
+	PreRoot  cid.Cid
+	PostRoot cid.Cid
+	vector   schema.TestVector
+}
+
+var _ Builder = (*BlockSeqVectorBuilder)(nil)
+
+// BlockSeqVector creates a new BlockSeqVectorBuilder. For usage details, read
+// the godocs on that type.
+func BlockSeqVector(metadata *schema.Metadata, selector schema.Selector, mode Mode, hints []string, pv ProtocolVersion) *BlockSeqVectorBuilder {
+	bc := &BuilderCommon{
+		Stage:           StagePreconditions,
+		ProtocolVersion: pv,
+	}
+	bc.Wallet = NewWallet()
+	bc.Randomness = NewRandomness()
+
+	b := &BlockSeqVectorBuilder{
+		BuilderCommon: bc,
+	}
+
+	b.StateTracker = NewStateTracker(bc, selector, &b.vector, pv.StateTree, pv.ActorsAdapter())
+	bc.Actors = NewActors(bc, b.StateTracker)
+
+	b.vector.Class = schema.ClassBlockSeq
+	b.vector.Meta = metadata
+	b.vector.Pre = &schema.Preconditions{}
+	b.vector.Selector = selector
+	b.vector.Hints = hints
+
+	bc.Assert = NewAsserter(metadata.ID, pv, mode == ModeLenientAssertions, suppliers{
+		messages: func() []*ApplicableMessage {
+			// only return messages that have actually been enrolled on
+			// accepted blocks.
+			return b.Blocks.Messages()
+		},
+		stateTracker: func() *StateTracker {
+			return b.StateTracker
+		},
+		actors:  func() *Actors { return bc.Actors },
+		preroot: func() cid.Cid { return b.PreRoot },
+	})
+
+	bc.Assert.enterStage(StagePreconditions)
+
+	return b
+}
+
+// SetInitialEpochOffset sets the initial epoch offset of this blockseq-class
+// vector. It MUST be called during the preconditions stage.
+func (b *BlockSeqVectorBuilder) SetInitialEpochOffset(epoch abi.ChainEpoch) {
+	if b.Stage != StagePreconditions {
+		panic("you can only call SetInitialEpochOffset at preconditions stage")
+	}
+	b.InitialEpochOffset = epoch
+}
+
+// CommitPreconditions flushes the state tree, recording the new CID in the
+// underlying test vector's precondition. It creates the StagedMessages and
+// Blocks object where messages will be staged, and blocks will be registered.
+//
+// This method progesses the builder into the "applies" stage and may only be
+// called during the "preconditions" stage.
+func (b *BlockSeqVectorBuilder) CommitPreconditions() {
+	if b.Stage != StagePreconditions {
+		panic("called CommitPreconditions at the wrong time")
+	}
+
+	// capture the preroot after applying all preconditions.
+	preroot := b.StateTracker.Flush()
+	b.PreRoot = preroot
+
+	// update the vector.
+	b.vector.Pre.Variants = []schema.Variant{{
+		ID:             b.ProtocolVersion.ID,
+		Epoch:          int64(b.InitialEpochOffset + b.ProtocolVersion.FirstEpoch),
+		NetworkVersion: uint(b.ProtocolVersion.Network),
+	}}
+	b.vector.Pre.StateTree = &schema.StateTree{RootCID: preroot}
+
+	b.Blocks = NewBlockSeq(b.InitialEpochOffset)
+	// messages travel signed in blocks.
+	b.StagedMessages = NewMessages(b.BuilderCommon, b.StateTracker).SetDefaults(Signed())
+
+	b.Stage = StageApplies
+	b.Assert.enterStage(StageApplies)
+}
+
+// CommitApplies derives and signs the headers of all registered blocks, and
+// executes the accepted blocks of every epoch as a tipset. It updates the
+// vector after every epoch.
+//
+// This method progresses the builder into the "checks" stage and may only be
+// called during the "applies" stage.
+func (b *BlockSeqVectorBuilder) CommitApplies() {
+	if b.Stage != StageApplies {
+		panic("called CommitApplies at the wrong time")
+	}
+
+	// discard the temporary state, and fork at the preroot.
+	b.StateTracker = b.StateTracker.Fork(b.PreRoot)
+
+	var (
+		ds = b.StateTracker.Stores.Datastore
+		bs = b.StateTracker.Stores.Blockstore
+	)
+
+	b.vector.Post = &schema.Postconditions{
+		StateTree: &schema.StateTree{RootCID: b.PreRoot},
+	}
+
+	head, err := NewBlockSeqHead(bs, b.PreRoot)
+	b.Assert.NoError(err)
+
+	var (
+		traces    []types.ExecutionTrace
+		prevEpoch = b.ProtocolVersion.FirstEpoch + b.InitialEpochOffset
+	)
+
+	driver := conformance.NewDriver(context.Background(), b.vector.Selector, conformance.DriverOpts{})
+	for _, be := range b.Blocks.All() {
+		var (
+			root      = head.StateRoot
+			execEpoch = b.ProtocolVersion.FirstEpoch + b.InitialEpochOffset + be.EpochOffset
+			tipset    = schema.Tipset{EpochOffset: int64(be.EpochOffset), BaseFee: *be.BaseFee.Int}
+			accepted  []cid.Cid
+		)
+
+		for i, blk := range be.Blocks {
+			bls, secp := b.blockMessages(blk)
+			blk.Header = b.deriveHeader(blk, i, bls, secp, execEpoch, be.BaseFee, head)
+
+			serialized := MustSerialize(blk.Header)
+			ab := schema.ApplyBlock{
+				EpochOffset: int64(be.EpochOffset),
+				Bytes:       serialized,
+				Header:      headerToSchema(blk.Header),
+				Expect:      blk.Expect,
+				Reason:      blk.Reason,
+			}
+			for _, am := range bls {
+				ab.BLSMessages = append(ab.BLSMessages, MustSerialize(am.Message))
+			}
+			for _, am := range secp {
+				ab.SECPMessages = append(ab.SECPMessages, MustSerialize(am.SignedMessage()))
+			}
+			b.vector.ApplyBlocks = append(b.vector.ApplyBlocks, ab)
+
+			// sanity check: the block must validate as expected.
+			_, err := head.ValidateBlock(bs, execEpoch, ab)
+			if blk.Expect != schema.BlockAccept {
+				b.Assert.Error(err, "block expected to be rejected (%s) is valid", blk.Reason)
+				continue
+			}
+			b.Assert.NoError(err, "block expected to be accepted is invalid")
+
+			b.Blocks.recordMessages(append(bls, secp...))
+			tipset.Blocks = append(tipset.Blocks, schema.Block{
+				MinerAddr:    blk.Miner.MinerActorAddr.ID,
+				WinCount:     blk.WinCount,
+				BLSMessages:  ab.BLSMessages,
+				SECPMessages: ab.SECPMessages,
+				BLSAggregate: ab.Header.BLSAggregate,
+			})
+			accepted = append(accepted, blk.Header.Cid())
+		}
+
+		if len(accepted) == 0 {
+			// all blocks at this epoch were rejected; this is a null round.
+			continue
+		}
+
+		executable, err := ExecutableTipset(bs, root, &tipset)
+		b.Assert.NoError(err, "failed to verify blocks at epoch: %d", be.EpochOffset)
+
+		ret, err := driver.ExecuteTipset(bs, ds, conformance.ExecuteTipsetParams{
+			Preroot:     root,
+			ParentEpoch: prevEpoch,
+			Tipset:      executable,
+			ExecEpoch:   execEpoch,
+			Rand:        b.Randomness,
+		})
+		b.Assert.NoError(err, "failed to apply blocks at epoch: %d", be.EpochOffset)
+
+		be.PostStateRoot = ret.PostStateRoot
+
+		for i, res := range ret.AppliedResults {
+			// store the receipt in the vector.
+			b.vector.Post.Receipts = append(b.vector.Post.Receipts, &schema.Receipt{
+				ExitCode:    int64(res.ExitCode),
+				ReturnValue: res.Return,
+				GasUsed:     res.GasUsed,
+			})
+
+			// store the trace.
+			traces = append(traces, res.ExecutionTrace)
+
+			// store the result and basefee in the original message being
+			// tracked by the BlockSeq, so we can do asserts.
+			mcid := ret.AppliedMessages[i].Cid()
+			for _, m := range b.Blocks.Messages() {
+				if m.Message.Cid() == mcid {
+					m.baseFee = be.BaseFee
+					m.Result = res
+					break
+				}
+			}
+		}
+
+		// Update the state and receipts root in the vector.
+		b.vector.Post.StateTree.RootCID = ret.PostStateRoot
+		b.vector.Post.ReceiptsRoots = append(b.vector.Post.ReceiptsRoots, ret.ReceiptsRoot)
+
+		head = BlockSeqHead{Parents: accepted, StateRoot: ret.PostStateRoot, Receipts: ret.ReceiptsRoot}
+		prevEpoch = execEpoch
+
+		// Update the state tree.
+		b.PostRoot = b.vector.Post.StateTree.RootCID
+		b.StateTracker.Load(b.PostRoot)
+	}
+
+	// Update the vector diagnostics.
+	b.vector.Diagnostics = EncodeTraces(traces)
+
+	// Advance the stage to checks.
+	b.Stage = StageChecks
+	b.Assert.enterStage(StageChecks)
+}
+
+// Finish signals to the builder that the checks stage is complete and that the
+// test vector can be finalized.
+//
+// This method progresses the builder into the "finished" stage and may only be
+// called during the "checks" stage.
+func (b *BlockSeqVectorBuilder) Finish() *schema.TestVector {
+	if b.Stage != StageChecks {
+		panic("called Finish at the wrong time")
+	}
+
+	car, err := EncodeCAR(b.StateTracker.Stores.DAGService, b.vector.Pre.StateTree.RootCID, b.vector.Post.StateTree.RootCID)
+	if err != nil {
+		panic(err)
+	}
+	b.vector.CAR = car
+	b.vector.Randomness = b.Randomness.Encode()
+
+	b.Stage = StageFinished
+	b.Assert = nil
+
+	return &b.vector
+}
+
+// blockMessages returns the messages of the supplied block, split by
+// signature type (see splitSigned). They must all be signed.
+func (b *BlockSeqVectorBuilder) blockMessages(blk *SeqBlock) (bls, secp []*ApplicableMessage) {
+	for _, am := range blk.Messages {
+		am.sign()
+		b.Assert.NotNil(am.Signature, "message %s enrolled in a block is unsigned", am.Message.Cid())
+	}
+	if len(blk.Messages) > 0 {
+		assertSignedSelector(b.Assert, &b.vector)
+	}
+	return splitSigned(blk.Messages)
+}
+
+// deriveHeader constructs and signs the header for the supplied block, which
+// carries the supplied BLS and secp256k1 messages and extends the supplied
+// head, applying any tamper functions registered on it.
+func (b *BlockSeqVectorBuilder) deriveHeader(blk *SeqBlock, idx int, bls, secp []*ApplicableMessage, epoch abi.ChainEpoch, baseFee abi.TokenAmount, head BlockSeqHead) *types.BlockHeader {
+	// like block producers, aggregate BLS signatures even if there are none.
+	agg := aggregateBLS(bls)
+
+	h := &types.BlockHeader{
+		Miner:  blk.Miner.MinerActorAddr.ID,
+		Ticket: &types.Ticket{VRFProof: fakeVRFProof("ticket", epoch, idx)},
+		ElectionProof: &types.ElectionProof{
+			WinCount: blk.WinCount,
+			VRFProof: fakeVRFProof("election", epoch, idx),
+		},
+		Parents:               head.Parents,
+		ParentWeight:          big.NewInt(int64(len(head.Parents))),
+		Height:                epoch,
+		ParentStateRoot:       head.StateRoot,
+		ParentMessageReceipts: head.Receipts,
+		Messages:              b.msgMeta(bls, secp),
+		BLSAggregate:          &crypto.Signature{Type: crypto.SigType(agg.Type), Data: agg.Data},
+		Timestamp:             uint64(epoch) * build.BlockDelaySecs,
+		ParentBaseFee:         baseFee,
+	}
+
+	for _, fn := range blk.tamperFns {
+		fn(h)
+	}
+
+	sb, err := h.SigningBytes()
+	b.Assert.NoError(err, "failed to obtain signing bytes for block")
+
+	worker := blk.Miner.WorkerAddr.Robust
+	h.BlockSig, err = b.Wallet.Sign(worker, sb)
+	b.Assert.NoError(err, "failed to sign block with worker key %s", worker)
+
+	for _, fn := range blk.tamperSignedFns {
+		fn(h)
+	}
+
+	return h
+}
+
+// msgMeta computes the CID of the MsgMeta object for the supplied messages,
+// storing the intermediate objects in the state tracker's stores. BLS
+// messages are referenced by the CID of the bare message, and secp256k1
+// messages by the CID of the SignedMessage.
+func (b *BlockSeqVectorBuilder) msgMeta(bls, secp []*ApplicableMessage) cid.Cid {
+	blsCids := make([]cid.Cid, 0, len(bls))
+	for _, am := range bls {
+		blsCids = append(blsCids, am.Message.Cid())
+	}
+	secpCids := make([]cid.Cid, 0, len(secp))
+	for _, am := range secp {
+		secpCids = append(secpCids, am.SignedMessage().Cid())
+	}
+
+	c, err := storeMsgMeta(b.StateTracker.Stores.ADTStore, blsCids, secpCids)
+	b.Assert.NoError(err)
+	return c
+}
+
+// fakeVRFProof returns deterministic bytes to be used in place of VRF proofs.
+// They are not valid proofs; drivers are not expected to verify them.
+func fakeVRFProof(kind string, epoch abi.ChainEpoch, idx int) []byte {
+	buf := make([]byte, 16)
+	binary.BigEndian.PutUint64(buf[:8], uint64(epoch))
+	binary.BigEndian.PutUint64(buf[8:], uint64(idx))
+	h := blake2b.Sum256(append([]byte(fmt.Sprintf("%s-", kind)), buf...))
+	return h[:]
+}
+
+// headerToSchema converts a block header to its informational schema form.
+func headerToSchema(h *types.BlockHeader) schema.BlockHeader {
+	ret := schema.BlockHeader{
+		Miner:                 h.Miner,
+		Parents:               h.Parents,
+		Height:                int64(h.Height),
+		ParentStateRoot:       h.ParentStateRoot,
+		ParentMessageReceipts: h.ParentMessageReceipts,
+		Messages:              h.Messages,
+		Timestamp:             h.Timestamp,
+		ForkSignaling:         h.ForkSignaling,
+	}
+	if h.ParentWeight.Int != nil {
+		ret.ParentWeight = *h.ParentWeight.Int
+	}
+	if h.ParentBaseFee.Int != nil {
+		ret.ParentBaseFee = *h.ParentBaseFee.Int
+	}
+	if h.Ticket != nil {
+		ret.Ticket = &schema.Ticket{VRFProof: h.Ticket.VRFProof}
+	}
+	if h.ElectionProof != nil {
+		ret.ElectionProof = &schema.ElectionProof{
+			WinCount: h.ElectionProof.WinCount,
+			VRFProof: h.ElectionProof.VRFProof,
+		}
+	}
+	for _, be := range h.BeaconEntries {
+		ret.BeaconEntries = append(ret.BeaconEntries, schema.BeaconEntry{Round: be.Round, Data: be.Data})
+	}
+	for _, p := range h.WinPoStProof {
+		ret.WinPoStProof = append(ret.WinPoStProof, schema.PoStProof{PoStProof: int64(p.PoStProof), ProofBytes: p.ProofBytes})
+	}
+	if h.BLSAggregate != nil {
+		ret.BLSAggregate = &schema.Signature{Type: schema.SignatureType(h.BLSAggregate.Type), Data: h.BLSAggregate.Data}
+	}
+	if h.BlockSig != nil {
+		ret.BlockSig = &schema.Signature{Type: schema.SignatureType(h.BlockSig.Type), Data: h.BlockSig.Data}
+	}
+	return ret
+}
@@ -41,12 +41,13 @@ func MessageVector(metadata *schema.Metadata, selector schema.Selector, mode Mod
 		ProtocolVersion: pv,
 	}
 	bc.Wallet = NewWallet()
+	bc.Randomness = NewRandomness()
 
 	b := &MessageVectorBuilder{
 		BuilderCommon: bc,
 	}
 
-	b.StateTracker = NewStateTracker(bc, selector, &b.vector, pv.StateTree, pv.Actors, pv.ZeroStateTree)
+	b.StateTracker = NewStateTracker(bc, selector, &b.vector, pv.StateTree, pv.ActorsAdapter())
 	b.Messages = NewMessages(bc, b.StateTracker)
 	bc.Actors = NewActors(bc, b.StateTracker)
 
@@ -125,10 +126,15 @@ func (b *MessageVectorBuilder) CommitApplies() {
 		}
 
 		epoch := int64(am.EpochOffset)
-		b.vector.ApplyMessages = append(b.vector.ApplyMessages, schema.Message{
+		msg := schema.Message{
 			Bytes:       MustSerialize(am.Message),
 			EpochOffset: &epoch,
-		})
+		}
+		if smsg := am.SignedMessage(); smsg != nil {
+			assertSignedSelector(b.Assert, &b.vector)
+			msg.Bytes, msg.Signed = MustSerialize(smsg), true
+		}
+		b.vector.ApplyMessages = append(b.vector.ApplyMessages, msg)
 
 		if am.Failed {
 			b.vector.Post.ApplyMessageFailures = append(b.vector.Post.ApplyMessageFailures, i)
@@ -168,6 +174,7 @@ func (b *MessageVectorBuilder) Finish() *schema.TestVector {
 		panic(err)
 	}
 	b.vector.CAR = car
+	b.vector.Randomness = b.Randomness.Encode()
 
 	msgs := b.Messages.All()
 	traces := make([]types.ExecutionTrace, 0, len(msgs))
@@ -2,6 +2,7 @@ package builders
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/chenjianmei111/test-vectors/schema"
 
@@ -31,7 +32,9 @@ import (
 //
 // To register blocks on a tipset, call Tipset#Block, supplying the miner, win
 // count, and the messages to enroll. Messages need to have been staged
-// previously.
+// previously. Blocks carrying signed messages (see Signed) are verified when
+// applied, and discarded if any of their signatures is invalid; the messages
+// they carry are then left without a result.
 type TipsetVectorBuilder struct {
 	*BuilderCommon
 
@@ -61,12 +64,13 @@ func TipsetVector(metadata *schema.Metadata, selector schema.Selector, mode Mode
 		ProtocolVersion: pv,
 	}
 	bc.Wallet = NewWallet()
+	bc.Randomness = NewRandomness()
 
 	b := &TipsetVectorBuilder{
 		BuilderCommon: bc,
 	}
 
-	b.StateTracker = NewStateTracker(bc, selector, &b.vector, pv.StateTree, pv.Actors, pv.ZeroStateTree)
+	b.StateTracker = NewStateTracker(bc, selector, &b.vector, pv.StateTree, pv.ActorsAdapter())
 	bc.Actors = NewActors(bc, b.StateTracker)
 
 	b.vector.Class = schema.ClassTipset
@@ -140,6 +144,12 @@ func (b *TipsetVectorBuilder) CommitPreconditions() {
 // every tipset application, and records the Rewards state existing at that
 // epoch under the Rewards object.
 //
+// Null rounds preceding a tipset are additionally executed one by one, as
+// empty tipsets, to surface the state after each null round's cron
+// execution. Their post state roots are set in Tipset#NullRounds, and the
+// Rewards state is recorded at every null round too, so Rewards has an
+// observation for every epoch in the sequence.
+//
 // It also sets the PostStateRoot on each applied Tipset, which can be used in
 // combination with StateTracker#Fork and Asserter#AtState to load and assert
 // on state at an interim tipset.
@@ -176,15 +186,49 @@ func (b *TipsetVectorBuilder) CommitApplies() {
 	for _, ts := range b.Tipsets.All() {
 		// Store the tipset in the vector.
 		b.vector.ApplyTipsets = append(b.vector.ApplyTipsets, ts.Tipset)
+		for _, blk := range ts.Blocks {
+			if blk.Signed() {
+				assertSignedSelector(b.Assert, &b.vector)
+			}
+		}
 
 		// Execute the tipset via the driver.
 		root := b.vector.Post.StateTree.RootCID
 		execEpoch := b.ProtocolVersion.FirstEpoch + b.InitialEpochOffset + abi.ChainEpoch(ts.EpochOffset)
-		ret, err := driver.ExecuteTipset(bs, ds, root, prevEpoch, &ts.Tipset, execEpoch)
+
+		// Verify the signatures of signed blocks, discarding invalid ones.
+		exec, err := ExecutableTipset(bs, root, &ts.Tipset)
+		b.Assert.NoError(err, "failed to verify the signatures of tipset at epoch: %d", ts.EpochOffset)
+
+		// Execute the preceding null rounds one by one, to observe them.
+		ts.NullRounds = b.executeNullRounds(driver, root, prevEpoch, execEpoch, ts.BaseFee)
+
+		ret, err := driver.ExecuteTipset(bs, ds, conformance.ExecuteTipsetParams{
+			Preroot:     root,
+			ParentEpoch: prevEpoch,
+			Tipset:      exec,
+			ExecEpoch:   execEpoch,
+			Rand:        b.Randomness,
+		})
 		b.Assert.NoError(err, "failed to apply tipset at epoch: %d", ts.EpochOffset)
 
 		ts.PostStateRoot = ret.PostStateRoot
 
+		// Executing the null rounds one by one must be equivalent to executing
+		// them along with the tipset; verify that by executing the tipset
+		// again on top of the last null round.
+		if n := len(ts.NullRounds); n > 0 {
+			check, err := driver.ExecuteTipset(bs, ds, conformance.ExecuteTipsetParams{
+				Preroot:     ts.NullRounds[n-1].PostStateRoot,
+				ParentEpoch: execEpoch - 1,
+				Tipset:      exec,
+				ExecEpoch:   execEpoch,
+				Rand:        b.Randomness,
+			})
+			b.Assert.NoError(err, "failed to apply tipset at epoch %d on top of its null rounds", ts.EpochOffset)
+			b.Assert.Equal(ret.PostStateRoot, check.PostStateRoot, "executing the null rounds before tipset at epoch %d one by one diverged from executing them with the tipset", ts.EpochOffset)
+		}
+
 		for i, res := range ret.AppliedResults {
 			// store the receipt in the vector.
 			b.vector.Post.Receipts = append(b.vector.Post.Receipts, &schema.Receipt{
@@ -219,11 +263,6 @@ func (b *TipsetVectorBuilder) CommitApplies() {
 		b.StateTracker.Load(b.PostRoot)
 
 		// record a rewards observation.
-		// TODO this is incomplete because it only records non-null rounds, but
-		//  the rewards policy can change during null rounds. Unfortunately
-		//  we don't get the intermediate roots through null rounds, so this
-		//  is not straightforward.
-		//  https://github.com/chenjianmei111/test-vectors/issues/91
 		b.Rewards.RecordAt(ts.EpochOffset)
 	}
 
@@ -235,6 +274,39 @@ func (b *TipsetVectorBuilder) CommitApplies() {
 	b.Assert.enterStage(StageChecks)
 }
 
+// executeNullRounds executes every null round between the supplied parent
+// and execution epochs as an empty tipset, starting from the supplied root.
+// The empty tipset at a null round's epoch runs the same cron execution as
+// the null round. It records a rewards observation after each null round, and
+// returns the null rounds along with their post state roots.
+func (b *TipsetVectorBuilder) executeNullRounds(driver *conformance.Driver, root cid.Cid, parentEpoch, execEpoch abi.ChainEpoch, baseFee big.Int) []NullRound {
+	var (
+		ds   = b.StateTracker.Stores.Datastore
+		bs   = b.StateTracker.Stores.Blockstore
+		base = b.ProtocolVersion.FirstEpoch + b.InitialEpochOffset
+		ret  []NullRound
+	)
+	for epoch := parentEpoch + 1; epoch < execEpoch; epoch++ {
+		offset := int64(epoch - base)
+		res, err := driver.ExecuteTipset(bs, ds, conformance.ExecuteTipsetParams{
+			Preroot:     root,
+			ParentEpoch: epoch - 1,
+			Tipset:      &schema.Tipset{EpochOffset: offset, BaseFee: baseFee},
+			ExecEpoch:   epoch,
+			Rand:        b.Randomness,
+		})
+		b.Assert.NoError(err, "failed to execute null round at epoch: %d", offset)
+
+		root = res.PostStateRoot
+		ret = append(ret, NullRound{EpochOffset: offset, PostStateRoot: root})
+
+		// record a rewards observation.
+		b.StateTracker.Load(root)
+		b.Rewards.RecordAt(offset)
+	}
+	return ret
+}
+
 // Finish signals to the builder that the checks stage is complete and that the
 // test vector can be finalized. It writes the test vector to the supplied
 // io.Writer.
@@ -251,6 +323,7 @@ func (b *TipsetVectorBuilder) Finish() *schema.TestVector {
 		panic(err)
 	}
 	b.vector.CAR = car
+	b.vector.Randomness = b.Randomness.Encode()
 
 	b.Stage = StageFinished
 	b.Assert = nil
@@ -0,0 +1,62 @@
+package builders
+
+import (
+	"sort"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+)
+
+// ProvingDeadlineCronPayload returns the payload of the cron event through
+// which a miner actor processes the end of its current proving deadline, and
+// re-enrolls for the end of the next one, as encoded by the actors version of
+// the vector.
+func (a *Actors) ProvingDeadlineCronPayload() []byte {
+	payload, err := a.st.ActorsAdapter.ProvingDeadlineCronPayload()
+	a.bc.Assert.NoError(err, "failed to serialize the proving deadline cron payload")
+	return payload
+}
+
+// EnrollCronEvent enrolls a deferred cron event for the miner in the storage
+// power actor, as the miner would through the EnrollCronEvent method. The
+// power actor delivers the payload to the miner through the
+// OnDeferredCronEvent method, during the cron tick of the supplied epoch, or
+// of the first epoch after it if cron does not run at that epoch.
+func (a *Actors) EnrollCronEvent(m Miner, epoch abi.ChainEpoch, payload []byte) {
+	err := a.st.ActorsAdapter.EnrollCronEvent(a.st, m.MinerActorAddr.ID, epoch, payload)
+	a.bc.Assert.NoError(err, "failed to enroll cron event for miner %s", m.MinerActorAddr.ID)
+}
+
+// CronEvents returns the cron events enrolled in the storage power actor,
+// sorted by epoch. Events of the same epoch are in delivery order.
+func (st *StateTracker) CronEvents() []CronEvent {
+	events, err := st.ActorsAdapter.CronEvents(st)
+	st.bc.Assert.NoError(err, "failed to load the cron event queue")
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Epoch < events[j].Epoch
+	})
+	return events
+}
+
+// CronEventQueueEq verifies that the cron events enrolled in the storage power
+// actor equal the expected ones, which must be sorted by epoch, and in delivery
+// order within an epoch.
+func (a *Asserter) CronEventQueueEq(expected ...CronEvent) {
+	actual := a.suppliers.stateTracker().CronEvents()
+	if len(expected) == 0 && len(actual) == 0 {
+		return
+	}
+	a.Equal(expected, actual, "cron event queues mismatch")
+}
+
+// MinerDeadlineEq verifies that, at the supplied epoch, which is the epoch of
+// the state asserted on, the current proving deadline of the miner has the
+// expected index, in the proving period starting at the expected epoch. The
+// miner advances its deadline when handling the proving deadline cron event.
+func (a *Asserter) MinerDeadlineEq(addr address.Address, epoch, periodStart abi.ChainEpoch, index uint64) {
+	ms := a.suppliers.stateTracker().MinerState(addr)
+	info, err := ms.DeadlineInfo(epoch)
+	a.NoError(err, "failed to get the deadline info of miner %s", addr)
+	a.Equal(periodStart, info.PeriodStart, "proving period starts mismatch for miner %s", addr)
+	a.Equal(index, info.Index, "current deadlines mismatch for miner %s", addr)
+}
@@ -1,62 +1,80 @@
 package builders
 
 import (
-	"bytes"
-	"compress/gzip"
-	"encoding/base64"
-	"encoding/json"
-	"path"
-	"time"
+	"math/big"
 
 	"github.com/chenjianmei111/lotus/chain/types"
 
 	"github.com/chenjianmei111/test-vectors/schema"
 )
 
-const LotusExecutionTraceV1 = "Lotus-ExecutionTrace-V1"
+// LotusExecutionTraceV1 is the diagnostics format emitted by the builders
+// before schema.DiagnosticsExecutionTraceV2. Vectors carrying it can still be
+// read through schema.Diagnostics#DecodeTraces.
+//
+// Deprecated: use schema.DiagnosticsLotusExecutionTraceV1.
+const LotusExecutionTraceV1 = schema.DiagnosticsLotusExecutionTraceV1
 
-// EncodeTraces takes a set of serialized lotus ExecutionTraces and writes them
-// to the test vector serialized diagnostic format.
+// EncodeTraces takes a set of lotus ExecutionTraces and writes them to the
+// test vector serialized diagnostic format, schema.DiagnosticsExecutionTraceV2.
+// Variable/volatile fields, e.g. durations and the locations of gas charges,
+// are not carried over, in order to remove noise and facilitate comparison and
+// diffing.
 func EncodeTraces(traces []types.ExecutionTrace) *schema.Diagnostics {
 	if len(traces) == 0 {
 		return nil
 	}
 
-	d := schema.Diagnostics{Format: LotusExecutionTraceV1}
-	serialized, err := json.Marshal(cleanTraces(traces))
+	d, err := schema.EncodeTraces(convertTraces(traces))
 	if err != nil {
 		panic(err)
 	}
+	return d
+}
 
-	data := bytes.NewBuffer(nil)
-	formatter := base64.NewEncoder(base64.StdEncoding, data)
-	compressor := gzip.NewWriter(formatter)
-	_, err = compressor.Write(serialized)
-	if err != nil {
-		panic(err)
-	}
-	if err := compressor.Close(); err != nil {
-		panic(err)
+// convertTraces recursively converts lotus execution traces to their schema
+// representation.
+func convertTraces(traces []types.ExecutionTrace) []schema.ExecutionTrace {
+	if len(traces) == 0 {
+		return nil
 	}
 
-	d.Data = data.Bytes()
-	return &d
-}
-
-// cleanTraces recursively strips variable/volatile fields from execution traces,
-// e.g. TimeTaken, in order to remove noise and facilitate comparison and diffing.
-func cleanTraces(t []types.ExecutionTrace) []types.ExecutionTrace {
-	for i := range t {
-		t[i].Duration = time.Duration(0)
-		t[i].Subcalls = cleanTraces(t[i].Subcalls)
-		for j := range t[i].GasCharges {
-			for k := range t[i].GasCharges[j].Location {
-				_, file := path.Split(t[i].GasCharges[j].Location[k].File)
-				t[i].GasCharges[j].Location[k].File = file
+	ret := make([]schema.ExecutionTrace, 0, len(traces))
+	for _, t := range traces {
+		conv := schema.ExecutionTrace{
+			Error:    t.Error,
+			Subcalls: convertTraces(t.Subcalls),
+		}
+		if m := t.Msg; m != nil {
+			value := new(big.Int)
+			if m.Value.Int != nil {
+				value.Set(m.Value.Int)
+			}
+			conv.Message = &schema.TraceMessage{
+				From:   m.From,
+				To:     m.To,
+				Nonce:  m.Nonce,
+				Value:  value,
+				Method: uint64(m.Method),
+				Params: m.Params,
 			}
-			t[i].GasCharges[j].TimeTaken = time.Duration(0)
-			t[i].GasCharges[j].Callers = nil
 		}
+		if r := t.MsgRct; r != nil {
+			conv.Receipt = &schema.Receipt{
+				ExitCode:    int64(r.ExitCode),
+				ReturnValue: r.Return,
+				GasUsed:     r.GasUsed,
+			}
+		}
+		for _, gc := range t.GasCharges {
+			conv.GasCharges = append(conv.GasCharges, schema.GasCharge{
+				Name:       gc.Name,
+				TotalGas:   gc.TotalGas,
+				ComputeGas: gc.ComputeGas,
+				StorageGas: gc.StorageGas,
+			})
+		}
+		ret = append(ret, conv)
 	}
-	return t
+	return ret
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
 
@@ -42,28 +44,78 @@ type Metadata = schema.Metadata
 //		force regeneration and overwrite any existing vectors in the output
 //		directory.
 //
+//  -check
+//		regenerate vectors in memory and compare them against those in the
+//		output directory, without writing anything. Lists the vectors that
+//		would be added, changed or left unchanged, and exits with a non-zero
+//		status if the output directory has drifted. Requires -o.
+//
+//  -prune
+//		delete vector files in the output directory that are no longer
+//		produced, e.g. because the vector was removed or renamed, or its
+//		variants were merged. Cannot be combined with -i or -v.
+//
 //  -i <include regex>
 //		regex inclusion filter to select a subset of vectors to execute; matched
 //		against the vector's ID.
 //
-//  TODO
 //  -v <protocol versions, comma-separated>
-//      protocol version variants to generate; if not provided, all supported
-//      protocol versions as declared by the vector will be attempted
+//		protocol version variants to generate; if not provided, all supported
+//		protocol versions as declared by the vector will be attempted. Ranges
+//		are supported, e.g. "smoke..actorsv2", "actorsv2..", "..smoke".
+//		Vectors supporting none of the selected versions are skipped. When
+//		writing to an output directory, the variants of the other versions
+//		found in the existing vector files are kept, and merged with the
+//		generated ones.
 //
 // Scripts can bundle test vectors into "groups". The generator will execute
 // each group in parallel, and will write each vector in a file:
 // <output_dir>/<group>--<vector_id>.json
+//
+// When generating into an output directory, the generator also writes a
+// MANIFEST file to it, listing the content hash and the file name of every
+// vector it produced. If vectors were filtered, the entries of the vectors
+// that were not generated are kept.
+//
+// Output is deterministic: vectors are printed in the order they were
+// declared, variants are ordered as per KnownProtocolVersions, vectors are
+// encoded in canonical JSON, and each vector is stamped with its content hash.
 type Generator struct {
 	OutputPath    string
 	Mode          OverwriteMode
 	IncludeFilter *regexp.Regexp
 
+	// Check, if true, compares generated vectors against those in the
+	// OutputPath instead of writing them.
+	Check bool
+
+	// Prune, if true, deletes vector files in the OutputPath that are no
+	// longer produced.
+	Prune bool
+
+	// VersionFilter, if not nil, is the set of IDs of the protocol versions
+	// to generate variants for.
+	VersionFilter map[string]struct{}
+
 	wg sync.WaitGroup
+
+	// stdoutTurn is closed when the last group started has printed its
+	// vectors to stdout; groups print in the order they were started.
+	stdoutTurn chan struct{}
+
+	// results tracks the outcome of every variant generation in this run.
+	results results
 }
 
 const brokenVectorPrefix = "x--"
 
+// ManifestFile is the name of the manifest file written to the output
+// directory. Each line contains the content hash and the file name of a
+// vector produced by the generator, separated by two spaces, sorted by file
+// name. The hash is that of the file on disk: if a changed vector was not
+// overwritten, it's the hash of the existing vector.
+const ManifestFile = "MANIFEST"
+
 // OverwriteMode is the mode used when overwriting existing test vector files.
 type OverwriteMode int
 
@@ -142,6 +194,25 @@ type VectorDef struct {
 	// TipsetFunc if non-nil, declares this vector as a tipset-class vector,
 	// generated by the specified builder.
 	TipsetFunc func(v *TipsetVectorBuilder)
+
+	// BlockSeqFunc if non-nil, declares this vector as a blockseq-class
+	// vector, generated by the specified builder.
+	BlockSeqFunc func(v *BlockSeqVectorBuilder)
+}
+
+// funcCount returns the number of generation functions set on this
+// VectorDef.
+func (vd *VectorDef) funcCount() (n int) {
+	if vd.MessageFunc != nil {
+		n++
+	}
+	if vd.TipsetFunc != nil {
+		n++
+	}
+	if vd.BlockSeqFunc != nil {
+		n++
+	}
+	return n
 }
 
 func NewGenerator() *Generator {
@@ -161,11 +232,24 @@ func NewGenerator() *Generator {
 	flag.BoolVar(&force, "f", false, forceUsage)
 	flag.BoolVar(&force, "force", false, forceUsage)
 
+	var check bool
+	const checkUsage = "regenerate vectors in memory and compare them against those in the output directory, without writing anything; exits with a non-zero status if they have drifted."
+	flag.BoolVar(&check, "check", false, checkUsage)
+
+	var prune bool
+	const pruneUsage = "delete vector files in the output directory that are no longer produced; cannot be combined with -i or -v."
+	flag.BoolVar(&prune, "prune", false, pruneUsage)
+
 	var includeFilter string
 	const includeFilterUsage = "regex inclusion filter to select a subset of vectors to execute; matched against the vector's ID or the vector group name."
 	flag.StringVar(&includeFilter, "i", "", includeFilterUsage)
 	flag.StringVar(&includeFilter, "include", "", includeFilterUsage)
 
+	var versionFilter string
+	const versionFilterUsage = "comma-separated protocol versions to generate variants for, supporting ranges like 'smoke..actorsv2', 'actorsv2..' or '..smoke'; if omitted, all versions supported by each vector are generated."
+	flag.StringVar(&versionFilter, "v", "", versionFilterUsage)
+	flag.StringVar(&versionFilter, "versions", "", versionFilterUsage)
+
 	flag.Parse()
 
 	var mode OverwriteMode
@@ -178,7 +262,21 @@ func NewGenerator() *Generator {
 		mode = OverwriteNone
 	}
 
-	gen := Generator{Mode: mode}
+	if check && (update || force) {
+		log.Fatalf("-check cannot be combined with -u or -f")
+	}
+	if check && outputDir == "" {
+		log.Fatalf("-check requires an output directory (-o) to compare against")
+	}
+
+	if prune && outputDir == "" {
+		log.Fatalf("-prune requires an output directory (-o)")
+	}
+	if prune && (includeFilter != "" || versionFilter != "") {
+		log.Fatalf("-prune cannot be combined with -i or -v, as it would delete the vectors that were filtered out")
+	}
+
+	gen := Generator{Mode: mode, Check: check, Prune: prune}
 
 	// If output directory is provided, we ensure it exists, or create it.
 	// Else, we'll output to stdout.
@@ -199,27 +297,62 @@ func NewGenerator() *Generator {
 		gen.IncludeFilter = exp
 	}
 
+	// If a protocol version filter has been provided, parse it.
+	if versionFilter != "" {
+		versions, err := ParseProtocolVersions(versionFilter)
+		if err != nil {
+			log.Fatalf("supplied protocol version filter %s is invalid: %s", versionFilter, err)
+		}
+		gen.VersionFilter = versions
+	}
+
 	return &gen
 }
 
+// Close waits for all groups to finish generating, and, if vectors were
+// generated into an output directory, writes the manifest and handles stale
+// vector files. If any vector variant failed to generate, it prints a summary
+// of the failures, and exits with a non-zero status. In check mode, it prints
+// the drift report, and also exits with a non-zero status if the output
+// directory has drifted.
 func (g *Generator) Close() {
 	g.wg.Wait()
+
+	failed := g.results.print(os.Stderr)
+	if g.OutputPath != "" {
+		g.closeOutput(failed)
+	}
+	drifted := g.Check && g.results.printDrift(os.Stdout)
+	if failed || drifted {
+		os.Exit(1)
+	}
 }
 
 func (g *Generator) Group(group string, vectors ...*VectorDef) {
 	// validate and filter vectors.
 	var generate []*VectorDef
 	for _, v := range vectors {
-		if v.MessageFunc != nil && v.TipsetFunc != nil {
+		switch fns := v.funcCount(); {
+		case fns > 1:
 			panic(fmt.Sprintf("vector with id %s had more than one function", v.Metadata.ID))
-		}
-		if v.MessageFunc == nil && v.TipsetFunc == nil {
+		case fns == 0:
 			panic(fmt.Sprintf("vector with id %s had no functions", v.Metadata.ID))
 		}
 		if id := v.Metadata.ID; g.IncludeFilter != nil && !g.IncludeFilter.MatchString(id) && !g.IncludeFilter.MatchString(group) {
 			log.Printf("skipping %s: does not match inclusion filter", id)
 			continue
 		}
+		if g.VersionFilter != nil {
+			versions := g.filterVersions(v.SupportedVersions)
+			if len(versions) == 0 {
+				log.Printf("skipping %s: supports none of the selected protocol versions", v.Metadata.ID)
+				g.results.skip(group, v.Metadata.ID)
+				continue
+			}
+			cpy := *v
+			cpy.SupportedVersions = versions
+			v = &cpy
+		}
 		generate = append(generate, v)
 	}
 
@@ -228,9 +361,14 @@ func (g *Generator) Group(group string, vectors ...*VectorDef) {
 		return
 	}
 
+	// groups generate in parallel, but print to stdout in order.
+	prevTurn, turn := g.stdoutTurn, make(chan struct{})
+	g.stdoutTurn = turn
+
 	g.wg.Add(1)
 	go func() {
 		defer g.wg.Done()
+		defer close(turn)
 
 		var tmpDir string
 		if g.OutputPath != "" {
@@ -247,44 +385,63 @@ func (g *Generator) Group(group string, vectors ...*VectorDef) {
 			tmpDir = dir
 		}
 
-		var wg sync.WaitGroup
-		for _, item := range generate {
+		var (
+			wg      sync.WaitGroup
+			printed = make([][]*schema.TestVector, len(generate))
+		)
+		for i, item := range generate {
 			wg.Add(1)
-			go func(item VectorDef) {
+			go func(i int, item VectorDef) {
 				defer wg.Done()
 
 				// generate variants.
-				variants := g.generateVariants(item)
+				variants := g.generateVariants(group, item)
 
-				// print to stdout.
+				// print to stdout, once all vectors in the group are generated.
 				if g.OutputPath == "" {
-					for _, v := range variants {
-						fmt.Println(string(v.MustMarshalJSON()))
-					}
+					printed[i] = variants
 					return
 				}
 
+				// merge the variants of the protocol versions that were
+				// filtered out from the existing vector files.
+				var superseded []string
+				if g.VersionFilter != nil {
+					var err error
+					variants, superseded, err = g.mergeExisting(group, &item, variants)
+					if err != nil {
+						log.Printf("failed to merge existing variants of vector %s: %s", item.Metadata.ID, err)
+						g.results.record(group, item.Metadata.ID, "", &AssertionFailure{Message: err.Error()})
+						return
+					}
+				}
+
 				for _, v := range variants {
 					var (
 						tmp      = vectorPath(tmpDir, group, &item, v)
 						existing = vectorPath(g.OutputPath, group, &item, v)
 					)
 
+					g.results.produce(filepath.Base(existing), v.Meta.ContentHash)
+
 					out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 					if err != nil {
 						log.Printf("failed to open file for writing %s: %s", tmp, err)
 						continue
 					}
 
-					enc := json.NewEncoder(out)
-					enc.SetIndent("", "\t")
-					if err := enc.Encode(v); err != nil {
+					if err := writeVector(out, v); err != nil {
 						log.Printf("failed to write json into file %s: %s", tmp, err)
 						continue
 					}
 
 					_ = out.Close()
 
+					if g.Check {
+						g.checkDrift(tmp, existing)
+						continue
+					}
+
 					switch _, err := os.Stat(existing); {
 					case err == nil:
 						// file exists.
@@ -304,6 +461,7 @@ func (g *Generator) Group(group string, vectors ...*VectorDef) {
 						if g.Mode == OverwriteNone {
 							// no overwrite requested, warn that the vector has changed but we're refusing to overwrite.
 							log.Printf("⚠️ WARNING: not writing %s: vector changed, use -u or -f to overwrite", existing)
+							g.produceExisting(existing)
 							continue
 						}
 						if g.Mode == OverwriteUpdate {
@@ -330,13 +488,318 @@ func (g *Generator) Group(group string, vectors ...*VectorDef) {
 					log.Printf("wrote test vector: %s", existing)
 				}
 
-			}(*item)
+				for _, f := range superseded {
+					g.results.remove(f)
+					p := filepath.Join(g.OutputPath, f)
+					if g.Check {
+						g.results.drift(DriftRemoved, p)
+						continue
+					}
+					if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+						log.Printf("failed to remove superseded vector %s: %s", p, err)
+						continue
+					}
+					log.Printf("removed superseded vector: %s", p)
+				}
+			}(i, *item)
 		}
 
 		wg.Wait()
+
+		if g.OutputPath != "" {
+			return
+		}
+		if prevTurn != nil {
+			<-prevTurn
+		}
+		for _, variants := range printed {
+			for _, v := range variants {
+				b, err := v.CanonicalJSON()
+				if err != nil {
+					log.Printf("failed to serialize vector %s: %s", v.Meta.ID, err)
+					continue
+				}
+				fmt.Println(string(b))
+			}
+		}
 	}()
 }
 
+// writeVector writes the canonical JSON encoding of the vector, indented with
+// tabs, to the supplied writer.
+func writeVector(w io.Writer, v *schema.TestVector) error {
+	b, err := v.CanonicalJSON()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", "\t"); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// filterVersions intersects the supplied protocol versions (defaulting to all
+// known versions if empty) with the versions selected by the VersionFilter.
+func (g *Generator) filterVersions(supported []ProtocolVersion) []ProtocolVersion {
+	if len(supported) == 0 {
+		supported = KnownProtocolVersions
+	}
+	var ret []ProtocolVersion
+	for _, pv := range supported {
+		if _, ok := g.VersionFilter[pv.ID]; ok {
+			ret = append(ret, pv)
+		}
+	}
+	return ret
+}
+
+// mergeExisting merges the supplied vectors, generated for the protocol
+// versions selected by the VersionFilter, with the variants of the other
+// protocol versions found in the existing files of the same vector in the
+// output directory, so that a filtered run doesn't drop them. It returns the
+// merged vectors, and the names of the existing files they supersede.
+func (g *Generator) mergeExisting(group string, item *VectorDef, generated []*schema.TestVector) ([]*schema.TestVector, []string, error) {
+	files, err := g.existingFiles(group, item)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// round-trip the generated vectors through JSON, so that they compare
+	// equal to those parsed from disk.
+	vectors := make([]*schema.TestVector, 0, len(generated)+len(files))
+	for _, v := range generated {
+		var cpy schema.TestVector
+		if err := json.Unmarshal(v.MustMarshalJSON(), &cpy); err != nil {
+			return nil, nil, err
+		}
+		vectors = append(vectors, &cpy)
+	}
+
+	for _, f := range files {
+		v, err := g.parseVectorFile(filepath.Join(g.OutputPath, f))
+		if err != nil {
+			return nil, nil, err
+		}
+		// retain the variants of the protocol versions that were not selected.
+		var retained []schema.Variant
+		for _, variant := range v.Pre.Variants {
+			if _, ok := g.VersionFilter[variant.ID]; !ok {
+				retained = append(retained, variant)
+			}
+		}
+		if len(retained) == 0 {
+			continue
+		}
+		v.Pre.Variants = retained
+		vectors = append(vectors, v)
+	}
+
+	merged := mergeVariants(item.Metadata.ID, vectors)
+
+	names := make(map[string]struct{}, len(merged))
+	for _, v := range merged {
+		names[vectorFilename(group, item, v)] = struct{}{}
+	}
+	var superseded []string
+	for _, f := range files {
+		if _, ok := names[f]; !ok {
+			superseded = append(superseded, f)
+		}
+	}
+	return merged, superseded, nil
+}
+
+// existingFiles returns the names of the files of the supplied vector in the
+// output directory, broken or not.
+func (g *Generator) existingFiles(group string, item *VectorDef) ([]string, error) {
+	entries, err := ioutil.ReadDir(g.OutputPath)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%s--%s--", group, item.Metadata.ID)
+
+	var files []string
+	for _, e := range entries {
+		name := strings.TrimPrefix(e.Name(), brokenVectorPrefix)
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		// the rest of the name must be the ID of the first variant.
+		if variant := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json"); protocolVersionIndex(variant) == -1 {
+			continue
+		}
+		files = append(files, e.Name())
+	}
+	return files, nil
+}
+
+// closeOutput writes the manifest to the output directory, and deletes the
+// stale vector files in it if pruning was requested, or warns about them
+// otherwise. In check mode, it records the changes to the manifest and the
+// stale files as drift instead. It does nothing if any variant failed to
+// generate, as the vector files of failed variants are missing from the run.
+//
+// If vectors were filtered, the manifest retains the entries of the vector
+// files that were not generated in this run, and there's no looking for
+// stale files, as the vectors that were filtered out would appear stale.
+func (g *Generator) closeOutput(failed bool) {
+	if failed {
+		log.Printf("not updating %s nor looking for stale vectors: some vector variants failed to generate", ManifestFile)
+		return
+	}
+
+	var (
+		manifestPath = filepath.Join(g.OutputPath, ManifestFile)
+		base         map[string]string
+		stale        []string
+		err          error
+	)
+	if g.IncludeFilter != nil || g.VersionFilter != nil {
+		base, err = g.readManifest(manifestPath)
+		if err != nil {
+			log.Printf("failed to read manifest %s: %s", manifestPath, err)
+			return
+		}
+	} else if stale, err = g.staleFiles(); err != nil {
+		log.Printf("failed to look for stale vectors: %s", err)
+		return
+	}
+
+	manifest := g.results.manifest(base)
+
+	if g.Check {
+		switch existing, err := ioutil.ReadFile(manifestPath); {
+		case os.IsNotExist(err):
+			g.results.drift(DriftAdded, manifestPath)
+		case err != nil:
+			log.Printf("failed to read manifest %s: %s", manifestPath, err)
+			g.results.drift(DriftChanged, manifestPath)
+		case bytes.Equal(existing, manifest):
+			g.results.drift(DriftUnchanged, manifestPath)
+		default:
+			g.results.drift(DriftChanged, manifestPath)
+		}
+		for _, f := range stale {
+			g.results.drift(DriftRemoved, filepath.Join(g.OutputPath, f))
+		}
+		return
+	}
+
+	if err := ioutil.WriteFile(manifestPath, manifest, 0644); err != nil {
+		log.Printf("failed to write manifest %s: %s", manifestPath, err)
+	}
+
+	for _, f := range stale {
+		p := filepath.Join(g.OutputPath, f)
+		if !g.Prune {
+			log.Printf("⚠️ WARNING: %s is no longer produced, use -prune to delete it", p)
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			log.Printf("failed to prune stale vector %s: %s", p, err)
+			continue
+		}
+		log.Printf("pruned stale vector: %s", p)
+	}
+}
+
+// produceExisting records the vector file at the supplied path, left as it
+// was on disk instead of being overwritten by the vector generated, with the
+// content hash of its contents, so that the manifest lists the vector on
+// disk.
+func (g *Generator) produceExisting(p string) {
+	hash, err := g.contentHash(p)
+	if err != nil {
+		log.Printf("failed to hash existing vector %s: %s", p, err)
+		return
+	}
+	g.results.produce(filepath.Base(p), hash)
+}
+
+// contentHash parses the vector at the given file path and returns its
+// content hash.
+func (g *Generator) contentHash(p string) (string, error) {
+	v, err := g.parseVectorFile(p)
+	if err != nil {
+		return "", err
+	}
+	return v.ContentHash()
+}
+
+// readManifest reads the entries of the manifest at the supplied path, mapping
+// file names to content hashes, and dropping those of the files that no
+// longer exist. A missing manifest has no entries.
+func (g *Generator) readManifest(p string) (map[string]string, error) {
+	raw, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed manifest line: %q", line)
+		}
+		hash, file := fields[0], fields[1]
+		if _, err := os.Stat(filepath.Join(g.OutputPath, file)); err != nil {
+			continue
+		}
+		entries[file] = hash
+	}
+	return entries, nil
+}
+
+// staleFiles returns the names of the vector files in the output directory
+// that were not produced in this run.
+func (g *Generator) staleFiles() ([]string, error) {
+	entries, err := ioutil.ReadDir(g.OutputPath)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		if !g.results.produced(e.Name()) {
+			stale = append(stale, e.Name())
+		}
+	}
+	return stale, nil
+}
+
+// checkDrift compares a freshly generated vector against the existing one,
+// and records whether the existing one would be added, changed or left
+// unchanged.
+func (g *Generator) checkDrift(tmp, existing string) {
+	switch _, err := os.Stat(existing); {
+	case os.IsNotExist(err):
+		g.results.drift(DriftAdded, existing)
+	case err != nil:
+		log.Printf("failed unexpectedly while checking if file exists: %s; err: %s", existing, err)
+		g.results.drift(DriftChanged, existing)
+	default:
+		eql, err := g.vectorsEqual(tmp, existing)
+		if err != nil {
+			log.Printf("failed to check new vs existing vector equality: %s", err)
+		}
+		if eql {
+			g.results.drift(DriftUnchanged, existing)
+		} else {
+			g.results.drift(DriftChanged, existing)
+		}
+	}
+}
+
 // vectorPath returns the filepath for the supplied vector, in the supplied
 // group, under the supplied directory. It prefixes files with `x--` if the
 // vector is known to be broken (i.e. carrying the schema.HintIncorrect hint).
@@ -403,7 +866,7 @@ func (g *Generator) vectorsEqual(apath, bpath string) (bool, error) {
 	return bytes.Equal(abytes, bbytes), nil
 }
 
-func (g *Generator) generateVariants(b VectorDef) []*schema.TestVector {
+func (g *Generator) generateVariants(group string, b VectorDef) []*schema.TestVector {
 	if len(b.SupportedVersions) == 0 {
 		b.SupportedVersions = KnownProtocolVersions
 	}
@@ -415,45 +878,58 @@ func (g *Generator) generateVariants(b VectorDef) []*schema.TestVector {
 	for _, version := range b.SupportedVersions {
 		log.Printf("generating vector [%s] ~~>> pv: [%s]", b.Metadata.ID, version.ID)
 
-		var vector Builder
-		// TODO: currently if an assertion fails, we call os.Exit(1), which
-		//  aborts all ongoing vector generations. The Asserter should
-		//  call runtime.Goexit() instead so only that goroutine is
-		//  cancelled. The assertion error must bubble up somehow.
-		switch {
-		case b.MessageFunc != nil:
-			v := MessageVector(b.Metadata, b.Selector, b.Mode, b.Hints, version)
-			b.MessageFunc(v)
-			vector = v
-		case b.TipsetFunc != nil:
-			v := TipsetVector(b.Metadata, b.Selector, b.Mode, b.Hints, version)
-			b.TipsetFunc(v)
-			vector = v
-		default:
-			panic("no generation function provided")
+		v, failure := buildVariant(b, version)
+		g.results.record(group, b.Metadata.ID, version.ID, failure)
+		if failure != nil {
+			log.Printf("failed to generate vector [%s] ~~>> pv: [%s]; stage: %s", b.Metadata.ID, version.ID, failure.Stage)
+			continue
 		}
-
-		// Finish the vector.
-		v := vector.Finish()
 		result = append(result, v)
 	}
 
-	uniq := make(map[[32]byte]*schema.TestVector)
-	for _, v := range result {
-		variants := v.Pre.Variants                  // stash the variants
-		v.Pre.Variants = nil                        // compare without variants
+	return mergeVariants(b.Metadata.ID, result)
+}
+
+// mergeVariants merges equivalent vectors, i.e. those differing only in their
+// variants and metadata, into a single vector carrying all their variants,
+// and keeping the metadata of the first one. It orders the result, and
+// stamps each vector with its content hash.
+func mergeVariants(id string, vectors []*schema.TestVector) []*schema.TestVector {
+	// merge equivalent variants, preserving the order in which vectors are
+	// first seen.
+	var (
+		ret  []*schema.TestVector
+		uniq = make(map[[32]byte]*schema.TestVector)
+	)
+	for _, v := range vectors {
+		variants, meta := v.Pre.Variants, v.Meta    // stash the variants and metadata
+		v.Pre.Variants, v.Meta = nil, nil           // compare without them
 		hash := blake2b.Sum256(v.MustMarshalJSON()) // hash the serialized form
+		v.Pre.Variants, v.Meta = variants, meta     // restore them
 		if merged, ok := uniq[hash]; ok {
 			// dedup.
 			merged.Pre.Variants = append(merged.Pre.Variants, variants...)
 			continue
 		}
-		v.Pre.Variants = variants // restore the variant
 		uniq[hash] = v
+		ret = append(ret, v)
+	}
+
+	// order variants, and then vectors by their first variant, as per
+	// KnownProtocolVersions, so that the output does not depend on the order
+	// of SupportedVersions.
+	for _, v := range ret {
+		variants := v.Pre.Variants
+		sort.SliceStable(variants, func(i, j int) bool {
+			return protocolVersionIndex(variants[i].ID) < protocolVersionIndex(variants[j].ID)
+		})
 	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return protocolVersionIndex(ret[i].Pre.Variants[0].ID) < protocolVersionIndex(ret[j].Pre.Variants[0].ID)
+	})
 
 	var merged [][]string
-	for _, vector := range uniq {
+	for _, vector := range ret {
 		var ids []string
 		for _, v := range vector.Pre.Variants {
 			ids = append(ids, v.ID)
@@ -461,16 +937,87 @@ func (g *Generator) generateVariants(b VectorDef) []*schema.TestVector {
 		merged = append(merged, ids)
 	}
 
-	log.Printf("merged equivalent variants for vector %s; deduped groups: %v", b.Metadata.ID, merged)
+	log.Printf("merged equivalent variants for vector %s; deduped groups: %v", id, merged)
 
-	var ret []*schema.TestVector
-	for _, v := range uniq {
-		ret = append(ret, v)
+	// stamp each vector with its content hash. The metadata is shared by all
+	// variants, so each vector gets its own copy.
+	for _, v := range ret {
+		hash, err := v.ContentHash()
+		if err != nil {
+			panic(fmt.Sprintf("failed to compute content hash of vector %s: %s", id, err))
+		}
+		meta := *v.Meta
+		meta.ContentHash = hash
+		v.Meta = &meta
 	}
 
 	return ret
 }
 
+// protocolVersionIndex returns the position of the protocol version with the
+// supplied ID in KnownProtocolVersions, or -1 if it's unknown.
+func protocolVersionIndex(id string) int {
+	for i, pv := range KnownProtocolVersions {
+		if pv.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// buildVariant builds the variant of the vector for the supplied protocol
+// version. The generation function runs in a dedicated goroutine, so that a
+// failed assertion (which terminates the goroutine) or a panic only aborts
+// this variant. In that case, the failure is returned.
+func buildVariant(b VectorDef, version ProtocolVersion) (*schema.TestVector, *AssertionFailure) {
+	var (
+		vector   Builder
+		asserter *Asserter
+		fn       func()
+	)
+	switch {
+	case b.MessageFunc != nil:
+		v := MessageVector(b.Metadata, b.Selector, b.Mode, b.Hints, version)
+		vector, asserter, fn = v, v.Assert, func() { b.MessageFunc(v) }
+	case b.TipsetFunc != nil:
+		v := TipsetVector(b.Metadata, b.Selector, b.Mode, b.Hints, version)
+		vector, asserter, fn = v, v.Assert, func() { b.TipsetFunc(v) }
+	case b.BlockSeqFunc != nil:
+		v := BlockSeqVector(b.Metadata, b.Selector, b.Mode, b.Hints, version)
+		vector, asserter, fn = v, v.Assert, func() { b.BlockSeqFunc(v) }
+	default:
+		panic("no generation function provided")
+	}
+
+	var (
+		done    = make(chan struct{})
+		result  *schema.TestVector
+		failure *AssertionFailure
+	)
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("panic while generating vector [%s] ~~>> pv: [%s]: %v\n%s", b.Metadata.ID, version.ID, r, debug.Stack())
+				failure = &AssertionFailure{Stage: asserter.stage, Message: fmt.Sprintf("panic: %v", r)}
+			}
+		}()
+
+		fn()
+
+		// Finish the vector.
+		result = vector.Finish()
+	}()
+	<-done
+
+	if result == nil && failure == nil {
+		// the goroutine exited early due to a failed assertion.
+		f, _ := asserter.Failure()
+		failure = &f
+	}
+	return result, failure
+}
+
 // ensureDirectory checks if the provided path is a directory. If yes, it
 // returns nil. If the path doesn't exist, it creates the directory and
 // returns nil. If the path is not a directory, or another error occurs, an
@@ -0,0 +1,109 @@
+package builders
+
+import (
+	"fmt"
+
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/chain/vm"
+	"github.com/ipfs/go-cid"
+
+	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
+	init0 "github.com/chenjianmei111/specs-actors/actors/builtin/init"
+)
+
+// ActorKind identifies a kind of builtin actor, independently of the actors
+// version. The code CID of a kind is resolved against the actors version of
+// the vector.
+type ActorKind string
+
+const (
+	AccountActor        = ActorKind("account")
+	MinerActor          = ActorKind("miner")
+	MultisigActor       = ActorKind("multisig")
+	PaymentChannelActor = ActorKind("paymentchannel")
+)
+
+// Of returns the code CID of the supplied actor kind. It panics if the kind
+// is unknown.
+func (c ActorCodes) Of(kind ActorKind) cid.Cid {
+	switch kind {
+	case AccountActor:
+		return c.Account
+	case MinerActor:
+		return c.Miner
+	case MultisigActor:
+		return c.Multisig
+	case PaymentChannelActor:
+		return c.PaymentChannel
+	}
+	panic(fmt.Sprintf("unknown actor kind: %s", kind))
+}
+
+// isInitExec returns whether the message calls the Exec method of the init
+// actor, whose number is the same across actors versions.
+func isInitExec(msg *types.Message) bool {
+	return msg.To == builtin0.InitActorAddr && msg.Method == builtin0.MethodsInit.Exec
+}
+
+// predictExecActor predicts the addresses of the actor the supplied init Exec
+// message creates, if applied on the current state: the robust address is
+// derived from the pubkey address of the sender and the message nonce, and the
+// ID address is the next ID of the init actor. It returns nil if the sender is
+// not an account.
+func (st *StateTracker) predictExecActor(msg *types.Message) *AddressHandle {
+	key, err := st.KeyAddress(msg.From)
+	if err != nil {
+		return nil
+	}
+	sender := AddressHandle{Robust: key}
+
+	// the layout of the init actor state is the same across actors versions.
+	var is init0.State
+	st.ActorState(builtin0.InitActorAddr, &is)
+
+	return &AddressHandle{
+		ID:     MustNewIDAddr(uint64(is.NextID)),
+		Robust: sender.NextActorAddress(msg.Nonce, 0),
+	}
+}
+
+// registerExecActor registers the actor created by an applied init Exec
+// message with Actors, if the message succeeded.
+func (st *StateTracker) registerExecActor(am *ApplicableMessage) {
+	if am.Result.ExitCode.IsError() {
+		return
+	}
+	var ret init0.ExecReturn
+	if err := Deserialize(am.Result.Return, &ret); err != nil {
+		return
+	}
+	st.bc.Actors.registerCreated(AddressHandle{ID: ret.IDAddress, Robust: ret.RobustAddress})
+}
+
+// ExecReturns returns an ApplyRetPredicate that passes if the message is an
+// init Exec message that created an actor with the addresses of the supplied
+// handle.
+func ExecReturns(expected AddressHandle) ApplyRetPredicate {
+	return func(ret *vm.ApplyRet) error {
+		var er init0.ExecReturn
+		if err := Deserialize(ret.Return, &er); err != nil {
+			return fmt.Errorf("failed to decode exec return: %w", err)
+		}
+		if er.IDAddress != expected.ID || er.RobustAddress != expected.Robust {
+			return fmt.Errorf("created actor was %s; expected %s", AddressHandle{ID: er.IDAddress, Robust: er.RobustAddress}, expected)
+		}
+		return nil
+	}
+}
+
+// ExecReturnsPredicted verifies that the supplied init Exec messages created
+// actors with the addresses the builder predicted when applying them; see
+// ApplicableMessage#PredictedActor.
+func (a *Asserter) ExecReturnsPredicted(ams ...*ApplicableMessage) {
+	for _, am := range ams {
+		a.NotNil(am.PredictedActor, "no actor predicted for message %s", am.Message.Cid())
+		a.NotNil(am.Result, "message %s was not applied", am.Message.Cid())
+		err := ExecReturns(*am.PredictedActor)(am.Result)
+		a.NoError(err, "message %s did not create the predicted actor", am.Message.Cid())
+	}
+}
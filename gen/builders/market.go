@@ -0,0 +1,138 @@
+package builders
+
+import (
+	"crypto/sha256"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/market"
+
+	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+)
+
+// MustNewPieceCID returns a piece CID derived from the supplied seed. It is a
+// well-formed unsealed commitment CID, but does not commit to any actual data.
+func MustNewPieceCID(seed string) cid.Cid {
+	digest := sha256.Sum256([]byte(seed))
+	h, err := multihash.Encode(digest[:], market0.PieceCIDPrefix.MhType)
+	if err != nil {
+		panic(err)
+	}
+	return cid.NewCidV1(market0.PieceCIDPrefix.Codec, h)
+}
+
+// SignDealProposal signs the supplied deal proposal with the key of the
+// signer, which must be an account created through this Wallet, and returns
+// the resulting client deal proposal. The signer is usually the robust
+// address of the client of the proposal.
+func (w *Wallet) SignDealProposal(signer address.Address, proposal market0.DealProposal) (*market0.ClientDealProposal, error) {
+	b, err := Serialize(&proposal)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := w.Sign(signer, b)
+	if err != nil {
+		return nil, err
+	}
+	return &market0.ClientDealProposal{
+		Proposal:        proposal,
+		ClientSignature: *sig,
+	}, nil
+}
+
+// ActiveDeal seeds the storage market actor with the supplied deal, published
+// and activated at the supplied epoch, as if its provider had proven a sector
+// holding it then, and returns its ID. The balances the deal requires are
+// escrowed and locked for both parties, backed by the balance of the market
+// actor; the parties are not debited. The deal is not signed by the client.
+//
+// Vectors can't activate deals through messages: the miner actor only does so
+// once the sealing proofs of the sector are verified. The sector holding the
+// deal can be seeded through MinerSectors.
+func (a *Actors) ActiveDeal(proposal market0.DealProposal, activation abi.ChainEpoch) abi.DealID {
+	id, escrowed, err := a.st.ActorsAdapter.SeedDeal(a.st, proposal, activation)
+	a.bc.Assert.NoError(err, "failed to seed deal")
+
+	actor := a.st.Header(builtin0.StorageMarketActorAddr)
+	actor.Balance = big.Add(actor.Balance, escrowed)
+	err = a.st.StateTree.SetActor(builtin0.StorageMarketActorAddr, actor)
+	a.bc.Assert.NoError(err, "failed to update the balance of the storage market actor")
+	return id
+}
+
+// MarketState loads the state of the storage market actor.
+func (st *StateTracker) MarketState() market.State {
+	actor := st.Header(builtin0.StorageMarketActorAddr)
+	ms, err := market.Load(st.Stores.ADTStore, actor)
+	st.bc.Assert.NoError(err, "failed to load the storage market actor state")
+	return ms
+}
+
+// MarketEscrowEq verifies that the escrow balance of the address in the
+// storage market actor equals the expected one. The address must be an ID
+// address.
+func (a *Asserter) MarketEscrowEq(addr address.Address, expected abi.TokenAmount) {
+	table, err := a.suppliers.stateTracker().MarketState().EscrowTable()
+	a.NoError(err, "failed to load the market escrow table")
+	actual, err := table.Get(addr)
+	a.NoError(err, "failed to get the market escrow balance of %s", addr)
+	a.Equal(expected, actual, "market escrow balances mismatch for address %s", addr)
+}
+
+// MarketLockedEq verifies that the locked balance of the address in the
+// storage market actor equals the expected one. The address must be an ID
+// address.
+func (a *Asserter) MarketLockedEq(addr address.Address, expected abi.TokenAmount) {
+	table, err := a.suppliers.stateTracker().MarketState().LockedTable()
+	a.NoError(err, "failed to load the market locked table")
+	actual, err := table.Get(addr)
+	a.NoError(err, "failed to get the market locked balance of %s", addr)
+	a.Equal(expected, actual, "market locked balances mismatch for address %s", addr)
+}
+
+// DealProposalEq verifies that the storage market actor tracks a proposal for
+// the deal, equal to the expected one.
+func (a *Asserter) DealProposalEq(id abi.DealID, expected market0.DealProposal) {
+	proposals, err := a.suppliers.stateTracker().MarketState().Proposals()
+	a.NoError(err, "failed to load the market deal proposals")
+	actual, found, err := proposals.Get(id)
+	a.NoError(err, "failed to get the proposal of deal %d", id)
+	a.True(found, "no proposal for deal %d", id)
+	a.Equal(expected, market0.DealProposal(*actual), "proposals mismatch for deal %d", id)
+}
+
+// DealProposalMissing verifies that the storage market actor tracks no
+// proposal for the deal.
+func (a *Asserter) DealProposalMissing(id abi.DealID) {
+	proposals, err := a.suppliers.stateTracker().MarketState().Proposals()
+	a.NoError(err, "failed to load the market deal proposals")
+	_, found, err := proposals.Get(id)
+	a.NoError(err, "failed to get the proposal of deal %d", id)
+	a.False(found, "unexpected proposal for deal %d", id)
+}
+
+// DealStateEq verifies that the state of the deal in the storage market actor
+// equals the expected one.
+func (a *Asserter) DealStateEq(id abi.DealID, expected market.DealState) {
+	states, err := a.suppliers.stateTracker().MarketState().States()
+	a.NoError(err, "failed to load the market deal states")
+	actual, found, err := states.Get(id)
+	a.NoError(err, "failed to get the state of deal %d", id)
+	a.True(found, "no state for deal %d", id)
+	a.Equal(expected, *actual, "states mismatch for deal %d", id)
+}
+
+// DealStateMissing verifies that the storage market actor tracks no state
+// for the deal, i.e. that the deal has not been activated.
+func (a *Asserter) DealStateMissing(id abi.DealID) {
+	states, err := a.suppliers.stateTracker().MarketState().States()
+	a.NoError(err, "failed to load the market deal states")
+	_, found, err := states.Get(id)
+	a.NoError(err, "failed to get the state of deal %d", id)
+	a.False(found, "unexpected state for deal %d", id)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/chenjianmei111/go-address"
 	"github.com/chenjianmei111/go-state-types/abi"
 	"github.com/chenjianmei111/go-state-types/big"
+	acrypto "github.com/chenjianmei111/go-state-types/crypto"
 
 	"github.com/chenjianmei111/lotus/chain/types"
 	"github.com/chenjianmei111/lotus/chain/vm"
@@ -45,6 +46,17 @@ type ApplicableMessage struct {
 	// Failed is true if this message was attempted to be applied and failed.
 	// In this case ApplicableMessage.Result will be nil.
 	Failed bool
+	// PredictedActor, for init Exec messages applied through the message
+	// vector builder, holds the addresses of the actor the message was
+	// predicted to create, based on the state it was applied on.
+	PredictedActor *AddressHandle
+	// Signature, if set, is the signature the message is emitted with, as a
+	// SignedMessage; see Signed. It's set when the message is applied, or
+	// enrolled in a block. It need not be valid: messages with invalid
+	// signatures fail to apply, and the blocks carrying them are discarded.
+	Signature *acrypto.Signature
+	// signFn signs the message, if it's to be emitted signed.
+	signFn func(msg *types.Message) *acrypto.Signature
 	// baseFee that was used when applying this message.
 	baseFee abi.TokenAmount
 }
@@ -68,7 +80,7 @@ func (m *Messages) All() []*ApplicableMessage {
 func (m *Messages) Include(msgs ...*ApplicableMessage) {
 	for _, am := range msgs {
 		amv := *am
-		amv.Result = nil
+		amv.Result, amv.Applied, amv.Failed = nil, false, false
 		m.messages = append(m.messages, &amv)
 	}
 }
@@ -107,6 +119,7 @@ func (m *Messages) Raw(from, to address.Address, method abi.MethodNum, params []
 	am := &ApplicableMessage{
 		EpochOffset: options.epochOffset,
 		Message:     msg,
+		signFn:      m.signer(options),
 	}
 
 	m.messages = append(m.messages, am)
@@ -119,7 +132,7 @@ func (m *Messages) Raw(from, to address.Address, method abi.MethodNum, params []
 func (m *Messages) ApplyOne(am *ApplicableMessage) {
 	var found bool
 	for i, other := range m.messages {
-		if other.Result != nil {
+		if other.Applied {
 			// message has been applied, continue.
 			continue
 		}
@@ -131,7 +144,7 @@ func (m *Messages) ApplyOne(am *ApplicableMessage) {
 		}
 		// verify that preceding messages have been applied.
 		// this will abort if unsatisfied.
-		m.bc.Assert.NotNil(other.Result, "preceding messages must have been applied when calling Apply*; index of first unapplied: %d", i)
+		m.bc.Assert.True(other.Applied, "preceding messages must have been applied when calling Apply*; index of first unapplied: %d", i)
 	}
 	m.bc.Assert.True(found, "ApplicableMessage not found")
 	m.st.ApplyMessage(am)
@@ -152,6 +165,11 @@ type msgOpts struct {
 	gasFeeCap   abi.TokenAmount
 	gasPremium  abi.TokenAmount
 	epochOffset abi.ChainEpoch
+
+	signed     bool
+	signer     address.Address
+	corruptSig bool
+	signature  *acrypto.Signature
 }
 
 // MsgOpt is an option configuring message value, gas parameters, execution
@@ -199,3 +217,38 @@ func EpochOffset(epoch abi.ChainEpoch) MsgOpt {
 		opts.epochOffset = epoch
 	}
 }
+
+// Signed signs a message with the key of its sender, and emits it as a
+// SignedMessage. Setting it as a default through Messages#SetDefaults signs
+// every message in the vector.
+func Signed() MsgOpt {
+	return func(opts *msgOpts) {
+		opts.signed = true
+	}
+}
+
+// SignedBy signs a message with the key of the supplied account, rather than
+// that of its sender, producing a well-formed but invalid signature.
+func SignedBy(signer address.Address) MsgOpt {
+	return func(opts *msgOpts) {
+		opts.signed = true
+		opts.signer = signer
+	}
+}
+
+// CorruptSignature signs a message like Signed does, and then flips the bits
+// of a byte of the signature, invalidating it.
+func CorruptSignature() MsgOpt {
+	return func(opts *msgOpts) {
+		opts.signed = true
+		opts.corruptSig = true
+	}
+}
+
+// WithSignature emits a message as a SignedMessage carrying the supplied
+// signature, as is.
+func WithSignature(sig acrypto.Signature) MsgOpt {
+	return func(opts *msgOpts) {
+		opts.signature = &sig
+	}
+}
@@ -2,8 +2,17 @@ package builders
 
 import (
 	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
 	"github.com/chenjianmei111/lotus/chain/types"
 
+	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+	multisig0 "github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+	verifreg0 "github.com/chenjianmei111/specs-actors/actors/builtin/verifreg"
+
+	multisig2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/multisig"
+
 	"github.com/chenjianmei111/lotus/chain/actors/builtin/multisig"
 	"github.com/chenjianmei111/lotus/chain/actors/builtin/paych"
 )
@@ -28,3 +37,142 @@ func (s *sugarMsg) MultisigMessage(from address.Address, builderFn func(multisig
 	s.m.bc.Assert.NoError(err, "failed to create multisig message")
 	return s.m.Message(msg, opts...)
 }
+
+// CreateMultisig enlists a message that creates a multisig actor through the
+// init actor, with the supplied signers and approval threshold. The initial
+// amount is sent to the new actor, and vests linearly over the unlock
+// duration, starting at the vesting start. Actors v0 take no vesting start,
+// and vest from the epoch of creation; the vesting start must be 0.
+func (s *sugarMsg) CreateMultisig(from address.Address, signers []address.Address, threshold uint64, vestingStart, unlockDuration abi.ChainEpoch, initial abi.TokenAmount, opts ...MsgOpt) *ApplicableMessage {
+	opts = append(opts, Value(initial))
+	return s.MultisigMessage(from, func(b multisig.MessageBuilder) (*types.Message, error) {
+		return b.Create(signers, threshold, vestingStart, unlockDuration, initial)
+	}, opts...)
+}
+
+// ProposeMultisig enlists a message that proposes a transaction to the
+// multisig actor. The proposal counts as the approval of the sender, and the
+// transaction is executed right away if that meets the threshold.
+func (s *sugarMsg) ProposeMultisig(from, msig, to address.Address, amount abi.TokenAmount, method abi.MethodNum, params []byte, opts ...MsgOpt) *ApplicableMessage {
+	opts = append(opts, Value(big.Zero()))
+	return s.MultisigMessage(from, func(b multisig.MessageBuilder) (*types.Message, error) {
+		return b.Propose(msig, to, amount, method, params)
+	}, opts...)
+}
+
+// ApproveMultisig enlists a message that approves a pending transaction of the
+// multisig actor. The proposal hash is optional; if supplied, the approval
+// fails if it does not match the pending transaction.
+func (s *sugarMsg) ApproveMultisig(from, msig address.Address, txID uint64, hash *multisig.ProposalHashData, opts ...MsgOpt) *ApplicableMessage {
+	opts = append(opts, Value(big.Zero()))
+	return s.MultisigMessage(from, func(b multisig.MessageBuilder) (*types.Message, error) {
+		return b.Approve(msig, txID, hash)
+	}, opts...)
+}
+
+// CancelMultisig enlists a message that cancels a pending transaction of the
+// multisig actor. Only the proposer of the transaction may cancel it. The
+// proposal hash is optional, as in ApproveMultisig.
+func (s *sugarMsg) CancelMultisig(from, msig address.Address, txID uint64, hash *multisig.ProposalHashData, opts ...MsgOpt) *ApplicableMessage {
+	opts = append(opts, Value(big.Zero()))
+	return s.MultisigMessage(from, func(b multisig.MessageBuilder) (*types.Message, error) {
+		return b.Cancel(msig, txID, hash)
+	}, opts...)
+}
+
+// ProposeAddSigner enlists a message that proposes the addition of a signer to
+// the multisig actor, optionally increasing the threshold by one. Signer
+// management is subject to the approval policy of the multisig, so it is
+// proposed as a transaction the multisig sends to itself.
+func (s *sugarMsg) ProposeAddSigner(from, msig, signer address.Address, increase bool, opts ...MsgOpt) *ApplicableMessage {
+	params := s.m.params(&multisig0.AddSignerParams{Signer: signer, Increase: increase})
+	return s.ProposeMultisig(from, msig, msig, big.Zero(), s.m.method(s.m.methods().Multisig.AddSigner), params, opts...)
+}
+
+// ProposeRemoveSigner enlists a message that proposes the removal of a signer
+// from the multisig actor, optionally decreasing the threshold by one.
+func (s *sugarMsg) ProposeRemoveSigner(from, msig, signer address.Address, decrease bool, opts ...MsgOpt) *ApplicableMessage {
+	params := s.m.params(&multisig0.RemoveSignerParams{Signer: signer, Decrease: decrease})
+	return s.ProposeMultisig(from, msig, msig, big.Zero(), s.m.method(s.m.methods().Multisig.RemoveSigner), params, opts...)
+}
+
+// ProposeSwapSigner enlists a message that proposes replacing a signer of the
+// multisig actor with another one.
+func (s *sugarMsg) ProposeSwapSigner(from, msig, oldSigner, newSigner address.Address, opts ...MsgOpt) *ApplicableMessage {
+	params := s.m.params(&multisig0.SwapSignerParams{From: oldSigner, To: newSigner})
+	return s.ProposeMultisig(from, msig, msig, big.Zero(), s.m.method(s.m.methods().Multisig.SwapSigner), params, opts...)
+}
+
+// ProposeChangeThreshold enlists a message that proposes changing the number
+// of approvals required by the multisig actor.
+func (s *sugarMsg) ProposeChangeThreshold(from, msig address.Address, threshold uint64, opts ...MsgOpt) *ApplicableMessage {
+	params := s.m.params(&multisig0.ChangeNumApprovalsThresholdParams{NewThreshold: threshold})
+	return s.ProposeMultisig(from, msig, msig, big.Zero(), s.m.method(s.m.methods().Multisig.ChangeNumApprovalsThreshold), params, opts...)
+}
+
+// ProposeLockBalance enlists a message that proposes locking an amount of the
+// balance of the multisig actor, vesting linearly over the unlock duration
+// from the start epoch. The multisig must not have a vesting schedule
+// already. Locking balance is only supported from actors v2.
+func (s *sugarMsg) ProposeLockBalance(from, msig address.Address, start, duration abi.ChainEpoch, amount abi.TokenAmount, opts ...MsgOpt) *ApplicableMessage {
+	params := s.m.params(&multisig2.LockBalanceParams{StartEpoch: start, UnlockDuration: duration, Amount: amount})
+	return s.ProposeMultisig(from, msig, msig, big.Zero(), s.m.method(s.m.methods().Multisig.LockBalance), params, opts...)
+}
+
+// AddMarketBalance enlists a message that escrows the supplied amount in the
+// storage market actor, on behalf of the beneficiary (a client, or a
+// provider miner).
+func (s *sugarMsg) AddMarketBalance(from, beneficiary address.Address, amount abi.TokenAmount, opts ...MsgOpt) *ApplicableMessage {
+	opts = append(opts, Value(amount))
+	return s.m.Typed(from, builtin0.StorageMarketActorAddr, MarketAddBalance(&beneficiary), opts...)
+}
+
+// WithdrawMarketBalance enlists a message that withdraws up to the supplied
+// amount from the escrow of the beneficiary in the storage market actor.
+func (s *sugarMsg) WithdrawMarketBalance(from, beneficiary address.Address, amount abi.TokenAmount, opts ...MsgOpt) *ApplicableMessage {
+	params := &market0.WithdrawBalanceParams{
+		ProviderOrClientAddress: beneficiary,
+		Amount:                  amount,
+	}
+	opts = append(opts, Value(big.Zero()))
+	return s.m.Typed(from, builtin0.StorageMarketActorAddr, MarketWithdrawBalance(params), opts...)
+}
+
+// PublishStorageDeals enlists a message that publishes the supplied signed
+// deals. It must be sent by the worker of the provider of the deals.
+func (s *sugarMsg) PublishStorageDeals(from address.Address, deals []market0.ClientDealProposal, opts ...MsgOpt) *ApplicableMessage {
+	params := &market0.PublishStorageDealsParams{Deals: deals}
+	opts = append(opts, Value(big.Zero()))
+	return s.m.Typed(from, builtin0.StorageMarketActorAddr, MarketPublishStorageDeals(params), opts...)
+}
+
+// AddVerifier enlists a message that grants the supplied datacap allowance to
+// a new verifier. It must be sent by the root key of the verified registry.
+func (s *sugarMsg) AddVerifier(from, verifier address.Address, allowance abi.StoragePower, opts ...MsgOpt) *ApplicableMessage {
+	params := &verifreg0.AddVerifierParams{Address: verifier, Allowance: allowance}
+	opts = append(opts, Value(big.Zero()))
+	return s.m.Typed(from, builtin0.VerifiedRegistryActorAddr, VerifregAddVerifier(params), opts...)
+}
+
+// RemoveVerifier enlists a message that removes a verifier. It must be sent by
+// the root key of the verified registry.
+func (s *sugarMsg) RemoveVerifier(from, verifier address.Address, opts ...MsgOpt) *ApplicableMessage {
+	opts = append(opts, Value(big.Zero()))
+	return s.m.Typed(from, builtin0.VerifiedRegistryActorAddr, VerifregRemoveVerifier(&verifier), opts...)
+}
+
+// AddVerifiedClient enlists a message that grants the supplied datacap
+// allowance to a client, out of the allowance of the verifier sending it.
+func (s *sugarMsg) AddVerifiedClient(from, client address.Address, allowance abi.StoragePower, opts ...MsgOpt) *ApplicableMessage {
+	params := &verifreg0.AddVerifiedClientParams{Address: client, Allowance: allowance}
+	opts = append(opts, Value(big.Zero()))
+	return s.m.Typed(from, builtin0.VerifiedRegistryActorAddr, VerifregAddVerifiedClient(params), opts...)
+}
+
+// UseBytes enlists a message that consumes datacap of a verified client. Only
+// the storage market actor may do so, when publishing verified deals.
+func (s *sugarMsg) UseBytes(from, client address.Address, size abi.StoragePower, opts ...MsgOpt) *ApplicableMessage {
+	params := &verifreg0.UseBytesParams{Address: client, DealSize: size}
+	opts = append(opts, Value(big.Zero()))
+	return s.m.Typed(from, builtin0.VerifiedRegistryActorAddr, VerifregUseBytes(params), opts...)
+}
@@ -12,13 +12,26 @@ import (
 	"github.com/chenjianmei111/specs-actors/actors/builtin/market"
 	"github.com/chenjianmei111/specs-actors/actors/builtin/miner"
 	"github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+	"github.com/chenjianmei111/specs-actors/actors/builtin/paych"
 	"github.com/chenjianmei111/specs-actors/actors/builtin/power"
 	"github.com/chenjianmei111/specs-actors/actors/builtin/reward"
+	"github.com/chenjianmei111/specs-actors/actors/builtin/verifreg"
 	"github.com/chenjianmei111/specs-actors/actors/runtime/proof"
 
+	miner2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/miner"
+	multisig2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/multisig"
+
 	"github.com/chenjianmei111/go-address"
+	cbg "github.com/whyrusleeping/cbor-gen"
 )
 
+// Typed calls resolve their method numbers from the actors version of the
+// vector; calls to methods that don't exist in that version fail the vector.
+// Params are the v0 types wherever their encoding is the same across actors
+// versions, and the types of the latest version otherwise. They are encoded
+// by the ActorsAdapter of the vector, which converts the latter, and verifies
+// the former; see ActorsAdapter#EncodeParams.
+
 func Transfer() TypedCall {
 	return func(_ *Messages) (method abi.MethodNum, params []byte) {
 		return builtin.MethodSend, []byte{}
@@ -30,14 +43,14 @@ func Transfer() TypedCall {
 // ----------------------------------------------------------------------------
 
 func AccountConstructor(params *address.Address) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsAccount.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Account.Constructor), m.params(params)
 	}
 }
 
 func AccountPubkeyAddress(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsAccount.PubkeyAddress, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Account.PubkeyAddress), m.params(params)
 	}
 }
 
@@ -46,49 +59,49 @@ func AccountPubkeyAddress(params *abi.EmptyValue) TypedCall {
 // ----------------------------------------------------------------------------
 
 func MarketConstructor(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.Constructor), m.params(params)
 	}
 }
 
 func MarketAddBalance(params *address.Address) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.AddBalance, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.AddBalance), m.params(params)
 	}
 }
 func MarketWithdrawBalance(params *market.WithdrawBalanceParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.WithdrawBalance, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.WithdrawBalance), m.params(params)
 	}
 }
 func MarketPublishStorageDeals(params *market.PublishStorageDealsParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.PublishStorageDeals, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.PublishStorageDeals), m.params(params)
 	}
 }
 func MarketVerifyDealsForActivation(params *market.VerifyDealsForActivationParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.VerifyDealsForActivation, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.VerifyDealsForActivation), m.params(params)
 	}
 }
 func MarketActivateDeals(params *market.ActivateDealsParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.ActivateDeals, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.ActivateDeals), m.params(params)
 	}
 }
 func MarketOnMinerSectorsTerminate(params *market.OnMinerSectorsTerminateParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.OnMinerSectorsTerminate, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.OnMinerSectorsTerminate), m.params(params)
 	}
 }
 func MarketComputeDataCommitment(params *market.ComputeDataCommitmentParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.ComputeDataCommitment, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.ComputeDataCommitment), m.params(params)
 	}
 }
 func MarketCronTick(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMarket.CronTick, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Market.CronTick), m.params(params)
 	}
 }
 
@@ -97,118 +110,192 @@ func MarketCronTick(params *abi.EmptyValue) TypedCall {
 // ----------------------------------------------------------------------------
 
 func MinerConstructor(params *power.MinerConstructorParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.Constructor), m.params(params)
 	}
 }
 func MinerControlAddresses(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ControlAddresses, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ControlAddresses), m.params(params)
 	}
 }
 func MinerChangeWorkerAddress(params *miner.ChangeWorkerAddressParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ChangeWorkerAddress, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ChangeWorkerAddress), m.params(params)
 	}
 }
 func MinerChangePeerID(params *miner.ChangePeerIDParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ChangePeerID, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ChangePeerID), m.params(params)
 	}
 }
-func MinerSubmitWindowedPoSt(params *miner.SubmitWindowedPoStParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.SubmitWindowedPoSt, MustSerialize(params)
+
+// MinerSubmitWindowedPoSt takes the v2 params; actors v0 ignore the chain
+// commitment.
+func MinerSubmitWindowedPoSt(params *miner2.SubmitWindowedPoStParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.SubmitWindowedPoSt), m.params(params)
 	}
 }
 func MinerPreCommitSector(params *miner.SectorPreCommitInfo) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.PreCommitSector, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.PreCommitSector), m.params(params)
 	}
 }
 func MinerProveCommitSector(params *miner.ProveCommitSectorParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ProveCommitSector, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ProveCommitSector), m.params(params)
 	}
 }
 func MinerExtendSectorExpiration(params *miner.ExtendSectorExpirationParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ExtendSectorExpiration, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ExtendSectorExpiration), m.params(params)
 	}
 }
 func MinerTerminateSectors(params *miner.TerminateSectorsParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.TerminateSectors, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.TerminateSectors), m.params(params)
 	}
 }
 func MinerDeclareFaults(params *miner.DeclareFaultsParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.DeclareFaults, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.DeclareFaults), m.params(params)
 	}
 }
 func MinerDeclareFaultsRecovered(params *miner.DeclareFaultsRecoveredParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.DeclareFaultsRecovered, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.DeclareFaultsRecovered), m.params(params)
 	}
 }
 func MinerOnDeferredCronEvent(params *miner.CronEventPayload) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.OnDeferredCronEvent, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.OnDeferredCronEvent), m.params(params)
 	}
 }
 func MinerCheckSectorProven(params *miner.CheckSectorProvenParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.CheckSectorProven, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.CheckSectorProven), m.params(params)
 	}
 }
+
+// MinerAddLockedFund is only supported by actors v0; it was replaced by
+// ApplyRewards in actors v2.
 func MinerAddLockedFund(params *big.Int) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.AddLockedFund, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.AddLockedFund), m.params(params)
 	}
 }
 func MinerReportConsensusFault(params *miner.ReportConsensusFaultParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ReportConsensusFault, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ReportConsensusFault), m.params(params)
 	}
 }
 func MinerWithdrawBalance(params *miner.WithdrawBalanceParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.WithdrawBalance, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.WithdrawBalance), m.params(params)
 	}
 }
 func MinerConfirmSectorProofsValid(params *builtin.ConfirmSectorProofsParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ConfirmSectorProofsValid, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ConfirmSectorProofsValid), m.params(params)
 	}
 }
 func MinerChangeMultiaddrs(params *miner.ChangeMultiaddrsParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMiner.ChangeMultiaddrs, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ChangeMultiaddrs), m.params(params)
+	}
+}
+func MinerCompactPartitions(params *miner.CompactPartitionsParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.CompactPartitions), m.params(params)
+	}
+}
+func MinerCompactSectorNumbers(params *miner.CompactSectorNumbersParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.CompactSectorNumbers), m.params(params)
+	}
+}
+
+// MinerApplyRewards is only supported from actors v2.
+func MinerApplyRewards(params *miner2.ApplyRewardParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ApplyRewards), m.params(params)
+	}
+}
+
+// MinerConfirmUpdateWorkerKey is only supported from actors v2.
+func MinerConfirmUpdateWorkerKey(params *abi.EmptyValue) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ConfirmUpdateWorkerKey), m.params(params)
+	}
+}
+
+// MinerRepayDebt is only supported from actors v2.
+func MinerRepayDebt(params *abi.EmptyValue) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.RepayDebt), m.params(params)
+	}
+}
+
+// MinerChangeOwnerAddress is only supported from actors v2.
+func MinerChangeOwnerAddress(params *address.Address) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Miner.ChangeOwnerAddress), m.params(params)
 	}
 }
 
 // ----------------------------------------------------------------------------
-// | MULTISIG -- use sugared methods for other operations.
+// | MULTISIG -- see also the sugared methods.
 // ----------------------------------------------------------------------------
 
+// MultisigConstructor takes the v2 params; actors v0 don't support a vesting
+// start epoch, so it must be zero for them.
+func MultisigConstructor(params *multisig2.ConstructorParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.Constructor), m.params(params)
+	}
+}
+func MultisigPropose(params *multisig.ProposeParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.Propose), m.params(params)
+	}
+}
+func MultisigApprove(params *multisig.TxnIDParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.Approve), m.params(params)
+	}
+}
+func MultisigCancel(params *multisig.TxnIDParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.Cancel), m.params(params)
+	}
+}
+
 func MultisigAddSigner(params *multisig.AddSignerParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMultisig.AddSigner, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.AddSigner), m.params(params)
 	}
 }
 func MultisigRemoveSigner(params *multisig.RemoveSignerParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMultisig.RemoveSigner, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.RemoveSigner), m.params(params)
 	}
 }
 func MultisigSwapSigner(params *multisig.SwapSignerParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMultisig.SwapSigner, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.SwapSigner), m.params(params)
 	}
 }
 func MultisigChangeNumApprovalsThreshold(params *multisig.ChangeNumApprovalsThresholdParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsMultisig.ChangeNumApprovalsThreshold, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.ChangeNumApprovalsThreshold), m.params(params)
+	}
+}
+
+// MultisigLockBalance is only supported from actors v2.
+func MultisigLockBalance(params *multisig2.LockBalanceParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Multisig.LockBalance), m.params(params)
 	}
 }
 
@@ -217,48 +304,51 @@ func MultisigChangeNumApprovalsThreshold(params *multisig.ChangeNumApprovalsThre
 // ----------------------------------------------------------------------------
 
 func PowerConstructor(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.Constructor), m.params(params)
 	}
 }
 func PowerCreateMiner(params *power.CreateMinerParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.CreateMiner, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.CreateMiner), m.params(params)
 	}
 }
 func PowerUpdateClaimedPower(params *power.UpdateClaimedPowerParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.UpdateClaimedPower, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.UpdateClaimedPower), m.params(params)
 	}
 }
 func PowerEnrollCronEvent(params *power.EnrollCronEventParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.EnrollCronEvent, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.EnrollCronEvent), m.params(params)
 	}
 }
 func PowerOnEpochTickEnd(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.OnEpochTickEnd, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.OnEpochTickEnd), m.params(params)
 	}
 }
 func PowerUpdatePledgeTotal(params *big.Int) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.UpdatePledgeTotal, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.UpdatePledgeTotal), m.params(params)
 	}
 }
+
+// PowerOnConsensusFault is only supported by actors v0; it was removed in
+// actors v2.
 func PowerOnConsensusFault(params *big.Int) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.OnConsensusFault, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.OnConsensusFault), m.params(params)
 	}
 }
 func PowerSubmitPoRepForBulkVerify(params *proof.SealVerifyInfo) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.SubmitPoRepForBulkVerify, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.SubmitPoRepForBulkVerify), m.params(params)
 	}
 }
 func PowerCurrentTotalPower(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsPower.CurrentTotalPower, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Power.CurrentTotalPower), m.params(params)
 	}
 }
 
@@ -267,42 +357,63 @@ func PowerCurrentTotalPower(params *abi.EmptyValue) TypedCall {
 // ----------------------------------------------------------------------------
 
 func RewardConstructor(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsReward.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Reward.Constructor), m.params(params)
 	}
 }
 func RewardAwardBlockReward(params *reward.AwardBlockRewardParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsReward.AwardBlockReward, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Reward.AwardBlockReward), m.params(params)
 	}
 }
 func RewardThisEpochReward(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsReward.ThisEpochReward, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Reward.ThisEpochReward), m.params(params)
 	}
 }
 func RewardUpdateNetworkKPI(params *big.Int) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsReward.UpdateNetworkKPI, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Reward.UpdateNetworkKPI), m.params(params)
 	}
 }
 
 // ----------------------------------------------------------------------------
-// | PAYCH -- use sugared methods.
+// | PAYCH -- see also the sugared methods.
 // ----------------------------------------------------------------------------
 
+func PaychConstructor(params *paych.ConstructorParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Paych.Constructor), m.params(params)
+	}
+}
+func PaychUpdateChannelState(params *paych.UpdateChannelStateParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Paych.UpdateChannelState), m.params(params)
+	}
+}
+func PaychSettle(params *abi.EmptyValue) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Paych.Settle), m.params(params)
+	}
+}
+func PaychCollect(params *abi.EmptyValue) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Paych.Collect), m.params(params)
+	}
+}
+
 // ----------------------------------------------------------------------------
 // | CRON
 // ----------------------------------------------------------------------------
 
 func CronConstructor(params *cron.ConstructorParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsCron.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Cron.Constructor), m.params(params)
 	}
 }
 func CronEpochTick(params *abi.EmptyValue) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsCron.EpochTick, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Cron.EpochTick), m.params(params)
 	}
 }
 
@@ -311,13 +422,63 @@ func CronEpochTick(params *abi.EmptyValue) TypedCall {
 // ----------------------------------------------------------------------------
 
 func InitConstructor(params *init_.ConstructorParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsInit.Constructor, MustSerialize(params)
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Init.Constructor), m.params(params)
 	}
 }
-func InitExec(params *init_.ExecParams) TypedCall {
-	return func(_ *Messages) (abi.MethodNum, []byte) {
-		return builtin.MethodsInit.Exec, MustSerialize(params)
+
+// InitExec creates an actor of the supplied kind through the init actor,
+// passing the supplied constructor params. The code CID is that of the actors
+// version of the vector.
+func InitExec(kind ActorKind, params cbg.CBORMarshaler) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Init.Exec), MustSerialize(&init_.ExecParams{
+			CodeCID:           m.st.ActorsAdapter.Codes().Of(kind),
+			ConstructorParams: MustSerialize(params),
+		})
+	}
+}
+
+// InitExecRaw calls the Exec method of the init actor with the supplied
+// params verbatim.
+func InitExecRaw(params *init_.ExecParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().Init.Exec), m.params(params)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// | VERIFREG
+// ----------------------------------------------------------------------------
+
+func VerifregConstructor(params *address.Address) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().VerifiedRegistry.Constructor), m.params(params)
+	}
+}
+func VerifregAddVerifier(params *verifreg.AddVerifierParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().VerifiedRegistry.AddVerifier), m.params(params)
+	}
+}
+func VerifregRemoveVerifier(params *address.Address) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().VerifiedRegistry.RemoveVerifier), m.params(params)
+	}
+}
+func VerifregAddVerifiedClient(params *verifreg.AddVerifiedClientParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().VerifiedRegistry.AddVerifiedClient), m.params(params)
+	}
+}
+func VerifregUseBytes(params *verifreg.UseBytesParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().VerifiedRegistry.UseBytes), m.params(params)
+	}
+}
+func VerifregRestoreBytes(params *verifreg.RestoreBytesParams) TypedCall {
+	return func(m *Messages) (abi.MethodNum, []byte) {
+		return m.method(m.methods().VerifiedRegistry.RestoreBytes), m.params(params)
 	}
 }
 
@@ -0,0 +1,124 @@
+package builders
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/cbor"
+)
+
+// ActorMethods are the method numbers of the builtin actors of an actors
+// version. Adapters fill them in explicitly from the method tables of their
+// version; methods that don't exist in that version are left zero, see
+// Messages#method.
+type ActorMethods struct {
+	Account struct {
+		Constructor   abi.MethodNum
+		PubkeyAddress abi.MethodNum
+	}
+	Init struct {
+		Constructor abi.MethodNum
+		Exec        abi.MethodNum
+	}
+	Cron struct {
+		Constructor abi.MethodNum
+		EpochTick   abi.MethodNum
+	}
+	Reward struct {
+		Constructor      abi.MethodNum
+		AwardBlockReward abi.MethodNum
+		ThisEpochReward  abi.MethodNum
+		UpdateNetworkKPI abi.MethodNum
+	}
+	Multisig struct {
+		Constructor                 abi.MethodNum
+		Propose                     abi.MethodNum
+		Approve                     abi.MethodNum
+		Cancel                      abi.MethodNum
+		AddSigner                   abi.MethodNum
+		RemoveSigner                abi.MethodNum
+		SwapSigner                  abi.MethodNum
+		ChangeNumApprovalsThreshold abi.MethodNum
+		LockBalance                 abi.MethodNum
+	}
+	Paych struct {
+		Constructor        abi.MethodNum
+		UpdateChannelState abi.MethodNum
+		Settle             abi.MethodNum
+		Collect            abi.MethodNum
+	}
+	Market struct {
+		Constructor              abi.MethodNum
+		AddBalance               abi.MethodNum
+		WithdrawBalance          abi.MethodNum
+		PublishStorageDeals      abi.MethodNum
+		VerifyDealsForActivation abi.MethodNum
+		ActivateDeals            abi.MethodNum
+		OnMinerSectorsTerminate  abi.MethodNum
+		ComputeDataCommitment    abi.MethodNum
+		CronTick                 abi.MethodNum
+	}
+	Power struct {
+		Constructor              abi.MethodNum
+		CreateMiner              abi.MethodNum
+		UpdateClaimedPower       abi.MethodNum
+		EnrollCronEvent          abi.MethodNum
+		OnEpochTickEnd           abi.MethodNum
+		UpdatePledgeTotal        abi.MethodNum
+		OnConsensusFault         abi.MethodNum
+		SubmitPoRepForBulkVerify abi.MethodNum
+		CurrentTotalPower        abi.MethodNum
+	}
+	Miner struct {
+		Constructor              abi.MethodNum
+		ControlAddresses         abi.MethodNum
+		ChangeWorkerAddress      abi.MethodNum
+		ChangePeerID             abi.MethodNum
+		SubmitWindowedPoSt       abi.MethodNum
+		PreCommitSector          abi.MethodNum
+		ProveCommitSector        abi.MethodNum
+		ExtendSectorExpiration   abi.MethodNum
+		TerminateSectors         abi.MethodNum
+		DeclareFaults            abi.MethodNum
+		DeclareFaultsRecovered   abi.MethodNum
+		OnDeferredCronEvent      abi.MethodNum
+		CheckSectorProven        abi.MethodNum
+		AddLockedFund            abi.MethodNum
+		ApplyRewards             abi.MethodNum
+		ReportConsensusFault     abi.MethodNum
+		WithdrawBalance          abi.MethodNum
+		ConfirmSectorProofsValid abi.MethodNum
+		ChangeMultiaddrs         abi.MethodNum
+		CompactPartitions        abi.MethodNum
+		CompactSectorNumbers     abi.MethodNum
+		ConfirmUpdateWorkerKey   abi.MethodNum
+		RepayDebt                abi.MethodNum
+		ChangeOwnerAddress       abi.MethodNum
+	}
+	VerifiedRegistry struct {
+		Constructor       abi.MethodNum
+		AddVerifier       abi.MethodNum
+		RemoveVerifier    abi.MethodNum
+		AddVerifiedClient abi.MethodNum
+		UseBytes          abi.MethodNum
+		RestoreBytes      abi.MethodNum
+	}
+}
+
+// methods returns the method numbers of the actors version of the vector.
+func (m *Messages) methods() ActorMethods {
+	return m.st.ActorsAdapter.Methods()
+}
+
+// method asserts that the supplied method number, picked from methods(),
+// exists in the actors version of the vector, and returns it.
+func (m *Messages) method(num abi.MethodNum) abi.MethodNum {
+	m.bc.Assert.NotZero(num, "method not supported by actors version %d", m.st.ActorsVersion)
+	return num
+}
+
+// params encodes the supplied params for the actors version of the vector;
+// see ActorsAdapter#EncodeParams.
+func (m *Messages) params(params cbor.Marshaler) []byte {
+	b, err := m.st.ActorsAdapter.EncodeParams(params)
+	m.bc.Assert.NoError(err, "failed to encode params for actors version %d", m.st.ActorsVersion)
+	return b
+}
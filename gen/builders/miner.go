@@ -0,0 +1,56 @@
+package builders
+
+import (
+	"math"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-bitfield"
+	"github.com/chenjianmei111/go-state-types/abi"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/miner"
+)
+
+// MinerState loads the state of the miner actor at the supplied address.
+func (st *StateTracker) MinerState(addr address.Address) miner.State {
+	ms, err := miner.Load(st.Stores.ADTStore, st.Header(addr))
+	st.bc.Assert.NoError(err, "failed to load the state of miner %s", addr)
+	return ms
+}
+
+// MinerSectorExpirationEq verifies that the miner tracks the sector, and that
+// it expires at the expected epoch.
+func (a *Asserter) MinerSectorExpirationEq(addr address.Address, n abi.SectorNumber, expected abi.ChainEpoch) {
+	info, err := a.suppliers.stateTracker().MinerState(addr).GetSector(n)
+	a.NoError(err, "failed to get sector %d of miner %s", n, addr)
+	a.NotNil(info, "no sector %d for miner %s", n, addr)
+	a.Equal(expected, info.Expiration, "expirations mismatch for sector %d of miner %s", n, addr)
+}
+
+// MinerPartitionSectorsEq verifies that the live and the faulty sectors of
+// the partition of the miner equal the expected ones. Live sectors are those
+// that have not been terminated, faulty or not.
+func (a *Asserter) MinerPartitionSectorsEq(addr address.Address, dlIdx, partIdx uint64, live, faulty []uint64) {
+	dl, err := a.suppliers.stateTracker().MinerState(addr).LoadDeadline(dlIdx)
+	a.NoError(err, "failed to load deadline %d of miner %s", dlIdx, addr)
+	part, err := dl.LoadPartition(partIdx)
+	a.NoError(err, "failed to load partition %d of deadline %d of miner %s", partIdx, dlIdx, addr)
+
+	liveSectors, err := part.LiveSectors()
+	a.NoError(err, "failed to load the live sectors of miner %s", addr)
+	faultySectors, err := part.FaultySectors()
+	a.NoError(err, "failed to load the faulty sectors of miner %s", addr)
+
+	a.Equal(live, a.bitfieldSectors(liveSectors), "live sectors mismatch for partition %d of deadline %d of miner %s", partIdx, dlIdx, addr)
+	a.Equal(faulty, a.bitfieldSectors(faultySectors), "faulty sectors mismatch for partition %d of deadline %d of miner %s", partIdx, dlIdx, addr)
+}
+
+// bitfieldSectors returns the sector numbers set in the bitfield, in
+// ascending order; it returns nil for an empty bitfield.
+func (a *Asserter) bitfieldSectors(bf bitfield.BitField) []uint64 {
+	sectors, err := bf.All(math.MaxUint64)
+	a.NoError(err, "failed to enumerate sectors")
+	if len(sectors) == 0 {
+		return nil
+	}
+	return sectors
+}
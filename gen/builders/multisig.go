@@ -0,0 +1,90 @@
+package builders
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/multisig"
+
+	multisig0 "github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+)
+
+// MultisigProposeReturn decodes the return value of an applied multisig
+// Propose message. The encoding is the same across actors versions.
+func MultisigProposeReturn(am *ApplicableMessage) multisig0.ProposeReturn {
+	var ret multisig0.ProposeReturn
+	MustDeserialize(am.Result.Return, &ret)
+	return ret
+}
+
+// MultisigApproveReturn decodes the return value of an applied multisig
+// Approve message. The encoding is the same across actors versions.
+func MultisigApproveReturn(am *ApplicableMessage) multisig0.ApproveReturn {
+	var ret multisig0.ApproveReturn
+	MustDeserialize(am.Result.Return, &ret)
+	return ret
+}
+
+// MultisigState loads the state of the multisig actor with the supplied
+// address.
+func (st *StateTracker) MultisigState(addr address.Address) multisig.State {
+	ms, err := multisig.Load(st.Stores.ADTStore, st.Header(addr))
+	st.bc.Assert.NoError(err, "failed to load the state of multisig %s", addr)
+	return ms
+}
+
+// MultisigSignersEq verifies that the multisig actor has the expected signers,
+// in order, and approval threshold.
+func (a *Asserter) MultisigSignersEq(addr address.Address, threshold uint64, signers ...address.Address) {
+	ms := a.suppliers.stateTracker().MultisigState(addr)
+
+	actualThreshold, err := ms.Threshold()
+	a.NoError(err, "failed to get the threshold of multisig %s", addr)
+	a.Equal(threshold, actualThreshold, "thresholds mismatch for multisig %s", addr)
+
+	actualSigners, err := ms.Signers()
+	a.NoError(err, "failed to get the signers of multisig %s", addr)
+	a.Equal(signers, actualSigners, "signers mismatch for multisig %s", addr)
+}
+
+// MultisigPendingTxnsEq verifies that the pending transactions of the multisig
+// actor, by ID, equal the expected ones.
+func (a *Asserter) MultisigPendingTxnsEq(addr address.Address, expected map[int64]multisig.Transaction) {
+	actual := make(map[int64]multisig.Transaction)
+	err := a.suppliers.stateTracker().MultisigState(addr).ForEachPendingTxn(func(id int64, txn multisig.Transaction) error {
+		actual[id] = txn
+		return nil
+	})
+	a.NoError(err, "failed to iterate over the pending transactions of multisig %s", addr)
+	if expected == nil {
+		expected = make(map[int64]multisig.Transaction)
+	}
+	a.Equal(expected, actual, "pending transactions mismatch for multisig %s", addr)
+}
+
+// MultisigVestingEq verifies that the vesting schedule of the multisig actor
+// has the expected start epoch, unlock duration and initial balance.
+func (a *Asserter) MultisigVestingEq(addr address.Address, start, duration abi.ChainEpoch, initial abi.TokenAmount) {
+	ms := a.suppliers.stateTracker().MultisigState(addr)
+
+	actualStart, err := ms.StartEpoch()
+	a.NoError(err, "failed to get the vesting start of multisig %s", addr)
+	a.Equal(start, actualStart, "vesting starts mismatch for multisig %s", addr)
+
+	actualDuration, err := ms.UnlockDuration()
+	a.NoError(err, "failed to get the unlock duration of multisig %s", addr)
+	a.Equal(duration, actualDuration, "unlock durations mismatch for multisig %s", addr)
+
+	actualInitial, err := ms.InitialBalance()
+	a.NoError(err, "failed to get the initial balance of multisig %s", addr)
+	a.Equal(initial, actualInitial, "initial balances mismatch for multisig %s", addr)
+}
+
+// MultisigLockedBalanceEq verifies that the balance of the multisig actor
+// still locked by its vesting schedule at the supplied epoch equals the
+// expected amount.
+func (a *Asserter) MultisigLockedBalanceEq(addr address.Address, epoch abi.ChainEpoch, expected abi.TokenAmount) {
+	actual, err := a.suppliers.stateTracker().MultisigState(addr).LockedBalance(epoch)
+	a.NoError(err, "failed to get the locked balance of multisig %s", addr)
+	a.Equal(expected, actual, "locked balances mismatch for multisig %s at epoch %d", addr, epoch)
+}
@@ -0,0 +1,130 @@
+package builders
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/crypto"
+
+	"github.com/chenjianmei111/lotus/chain/vm"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// Randomness is the randomness source served to the VM while a vector is
+// being generated.
+//
+// Vector authors declare rules through Chain, Beacon or Rule. When the VM
+// requests randomness, the first rule matching the kind, domain separation
+// tag, epoch and entropy of the request is served. Unmatched requests get
+// schema.DefaultRandomness, just like drivers will do.
+//
+// Calling Record enables recording mode, where every request made during
+// generation is captured along with the value served, so that it can be
+// serialized into the vector.
+//
+// Epochs are absolute, as that's what the VM requests randomness for; use
+// ProtocolVersion.FirstEpoch to derive them from epoch offsets.
+type Randomness struct {
+	lk        sync.Mutex
+	rules     schema.Randomness
+	recording bool
+	recorded  schema.Randomness
+}
+
+var _ vm.Rand = (*Randomness)(nil)
+
+// NewRandomness creates a new Randomness source with no rules.
+func NewRandomness() *Randomness {
+	return &Randomness{}
+}
+
+// Chain declares a rule that serves ret when chain randomness is requested
+// with the supplied domain separation tag, epoch and entropy.
+func (r *Randomness) Chain(dst crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte, ret []byte) {
+	r.Rule(schema.RandomnessChain, dst, epoch, entropy, ret)
+}
+
+// Beacon declares a rule that serves ret when beacon randomness is requested
+// with the supplied domain separation tag, epoch and entropy.
+func (r *Randomness) Beacon(dst crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte, ret []byte) {
+	r.Rule(schema.RandomnessBeacon, dst, epoch, entropy, ret)
+}
+
+// Rule declares a rule that serves ret when randomness of the specified kind
+// is requested with the supplied domain separation tag, epoch and entropy.
+// Rules are matched in declaration order.
+func (r *Randomness) Rule(kind schema.RandomnessKind, dst crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte, ret []byte) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	r.rules = append(r.rules, schema.RandomnessMatch{
+		On: schema.RandomnessRule{
+			Kind:                kind,
+			DomainSeparationTag: int64(dst),
+			Epoch:               int64(epoch),
+			Entropy:             entropy,
+		},
+		Return: ret,
+	})
+}
+
+// Record enables recording mode. From here on, every randomness request made
+// by the VM is captured and serialized into the vector.
+func (r *Randomness) Record() {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	r.recording = true
+}
+
+// GetChainRandomness implements vm.Rand.
+func (r *Randomness) GetChainRandomness(_ context.Context, pers crypto.DomainSeparationTag, round abi.ChainEpoch, entropy []byte) ([]byte, error) {
+	return r.serve(schema.RandomnessChain, pers, round, entropy), nil
+}
+
+// GetBeaconRandomness implements vm.Rand.
+func (r *Randomness) GetBeaconRandomness(_ context.Context, pers crypto.DomainSeparationTag, round abi.ChainEpoch, entropy []byte) ([]byte, error) {
+	return r.serve(schema.RandomnessBeacon, pers, round, entropy), nil
+}
+
+func (r *Randomness) serve(kind schema.RandomnessKind, dst crypto.DomainSeparationTag, epoch abi.ChainEpoch, entropy []byte) []byte {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	requested := schema.RandomnessRule{
+		Kind:                kind,
+		DomainSeparationTag: int64(dst),
+		Epoch:               int64(epoch),
+		Entropy:             entropy,
+	}
+
+	ret, ok := r.rules.Match(requested)
+	if ok {
+		// declared rules are always serialized; no need to record.
+		return ret
+	}
+
+	ret = []byte(schema.DefaultRandomness)
+	if _, seen := r.recorded.Match(requested); r.recording && !seen {
+		r.recorded = append(r.recorded, schema.RandomnessMatch{On: requested, Return: ret})
+	}
+	return ret
+}
+
+// Encode returns the randomness to serialize into the vector: the declared
+// rules, followed by the requests captured in recording mode. It returns nil
+// if there's nothing to serialize.
+func (r *Randomness) Encode() schema.Randomness {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if len(r.rules)+len(r.recorded) == 0 {
+		return nil
+	}
+	ret := make(schema.Randomness, 0, len(r.rules)+len(r.recorded))
+	ret = append(ret, r.rules...)
+	ret = append(ret, r.recorded...)
+	return ret
+}
@@ -0,0 +1,237 @@
+package builders
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// VariantFailure records the failure to generate a variant of a vector.
+type VariantFailure struct {
+	Group   string
+	ID      string
+	Variant string
+	AssertionFailure
+}
+
+// DriftStatus is the status of a vector file when checking for drift.
+type DriftStatus string
+
+const (
+	// DriftAdded indicates that the vector file does not exist.
+	DriftAdded = DriftStatus("added")
+	// DriftChanged indicates that the vector file exists, but differs.
+	DriftChanged = DriftStatus("changed")
+	// DriftUnchanged indicates that the vector file is up to date.
+	DriftUnchanged = DriftStatus("unchanged")
+	// DriftRemoved indicates that the vector file is no longer produced.
+	DriftRemoved = DriftStatus("removed")
+)
+
+// results tracks the outcome of variant generations across a run.
+type results struct {
+	lk        sync.Mutex
+	attempted int
+	failures  []VariantFailure
+	skipped   []string
+	drifts    map[DriftStatus][]string
+
+	// files maps the names of the vector files produced to their content
+	// hashes.
+	files map[string]string
+
+	// removed is the set of names of the vector files removed, as they were
+	// superseded by the files produced.
+	removed map[string]struct{}
+}
+
+// record records the outcome of a variant generation; failure is nil if the
+// variant was generated successfully.
+func (r *results) record(group, id, variant string, failure *AssertionFailure) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	r.attempted++
+	if failure != nil {
+		r.failures = append(r.failures, VariantFailure{
+			Group:            group,
+			ID:               id,
+			Variant:          variant,
+			AssertionFailure: *failure,
+		})
+	}
+}
+
+// skip records that a vector was skipped, because it supports none of the
+// selected protocol versions.
+func (r *results) skip(group, id string) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	r.skipped = append(r.skipped, group+"--"+id)
+}
+
+// print writes a summary of skipped vectors and a table of failures to the
+// supplied writer, and returns whether there were any failures.
+func (r *results) print(w io.Writer) bool {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if len(r.skipped) > 0 {
+		sort.Strings(r.skipped)
+		_, _ = fmt.Fprintf(w, "\n⏩  %d vectors skipped, as they support none of the selected protocol versions:\n", len(r.skipped))
+		for _, s := range r.skipped {
+			_, _ = fmt.Fprintf(w, "\t- %s\n", s)
+		}
+	}
+
+	if len(r.failures) == 0 {
+		return false
+	}
+
+	sort.Slice(r.failures, func(i, j int) bool {
+		a, b := r.failures[i], r.failures[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Variant < b.Variant
+	})
+
+	_, _ = fmt.Fprintf(w, "\n❌  %d of %d vector variants failed to generate:\n\n", len(r.failures), r.attempted)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "GROUP\tVECTOR\tVARIANT\tSTAGE\tERROR")
+	for _, f := range r.failures {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", f.Group, f.ID, f.Variant, f.Stage, summarizeFailure(f.Message))
+	}
+	_ = tw.Flush()
+	return true
+}
+
+// summarizeFailure condenses a (usually multi-line) testify failure message
+// into a single line, retaining the error and the user-supplied message.
+func summarizeFailure(msg string) string {
+	var parts []string
+	for _, line := range strings.Split(msg, "\n") {
+		line = strings.TrimSpace(line)
+		for _, label := range []string{"Error:", "Messages:"} {
+			if strings.HasPrefix(line, label) {
+				parts = append(parts, strings.TrimSpace(strings.TrimPrefix(line, label)))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, strings.Join(strings.Fields(msg), " "))
+	}
+
+	ret := strings.Join(parts, "; ")
+	const max = 120
+	if len(ret) > max {
+		ret = ret[:max] + "…"
+	}
+	return ret
+}
+
+// drift records the drift status of a vector file.
+func (r *results) drift(status DriftStatus, file string) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.drifts == nil {
+		r.drifts = make(map[DriftStatus][]string)
+	}
+	r.drifts[status] = append(r.drifts[status], file)
+}
+
+// printDrift writes the drift report to the supplied writer, and returns
+// whether any vector file would be added, changed or removed.
+func (r *results) printDrift(w io.Writer) bool {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	icons := map[DriftStatus]string{
+		DriftAdded:     "+",
+		DriftChanged:   "~",
+		DriftUnchanged: "=",
+		DriftRemoved:   "-",
+	}
+	for _, status := range []DriftStatus{DriftAdded, DriftChanged, DriftRemoved, DriftUnchanged} {
+		files := r.drifts[status]
+		sort.Strings(files)
+		for _, f := range files {
+			_, _ = fmt.Fprintf(w, "%s %s\n", icons[status], f)
+		}
+	}
+
+	added, changed, removed, unchanged := len(r.drifts[DriftAdded]), len(r.drifts[DriftChanged]), len(r.drifts[DriftRemoved]), len(r.drifts[DriftUnchanged])
+	_, _ = fmt.Fprintf(w, "\nadded: %d, changed: %d, removed: %d, unchanged: %d\n", added, changed, removed, unchanged)
+	return added+changed+removed > 0
+}
+
+// produce records that a vector file was produced, with the supplied content
+// hash.
+func (r *results) produce(file, hash string) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.files == nil {
+		r.files = make(map[string]string)
+	}
+	r.files[file] = hash
+}
+
+// produced returns whether the vector file was produced.
+func (r *results) produced(file string) bool {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	_, ok := r.files[file]
+	return ok
+}
+
+// remove records that a vector file was removed, as it was superseded by the
+// files produced.
+func (r *results) remove(file string) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if r.removed == nil {
+		r.removed = make(map[string]struct{})
+	}
+	r.removed[file] = struct{}{}
+}
+
+// manifest returns the contents of the manifest of the vector files produced,
+// on top of the supplied entries of vector files not produced in this run,
+// mapping file names to content hashes; see ManifestFile.
+func (r *results) manifest(base map[string]string) []byte {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	entries := make(map[string]string, len(base)+len(r.files))
+	for f, hash := range base {
+		if _, ok := r.removed[f]; !ok {
+			entries[f] = hash
+		}
+	}
+	for f, hash := range r.files {
+		entries[f] = hash
+	}
+
+	files := make([]string, 0, len(entries))
+	for f := range entries {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	var b strings.Builder
+	for _, f := range files {
+		_, _ = fmt.Fprintf(&b, "%s  %s\n", entries[f], f)
+	}
+	return []byte(b.String())
+}
@@ -9,7 +9,8 @@ import (
 )
 
 // RewardSummary holds the state we care about insofar rewards are concerned, at
-// every epoch where we perform an observation.
+// every epoch where we perform an observation. Tipset-class vectors perform an
+// observation at every epoch, including null rounds.
 type RewardSummary struct {
 	Treasury           abi.TokenAmount
 	EpochReward        abi.TokenAmount
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/chenjianmei111/go-state-types/cbor"
 	cbg "github.com/whyrusleeping/cbor-gen"
 )
 
@@ -23,6 +24,27 @@ func Serialize(i cbg.CBORMarshaler) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Transcode serializes the supplied value, deserializes it into out, and
+// verifies that out serializes to the same bytes. It converts values between
+// types of different actors versions that share the same encoding.
+func Transcode(in cbg.CBORMarshaler, out cbor.Er) error {
+	b, err := Serialize(in)
+	if err != nil {
+		return err
+	}
+	if err := Deserialize(b, out); err != nil {
+		return fmt.Errorf("failed to decode %T as %T: %w", in, out, err)
+	}
+	again, err := Serialize(out)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(b, again) {
+		return fmt.Errorf("%T does not encode as %T", in, out)
+	}
+	return nil
+}
+
 func MustDeserialize(b []byte, out interface{}) {
 	if err := Deserialize(b, out); err != nil {
 		panic(err)
@@ -0,0 +1,240 @@
+package builders
+
+import (
+	"fmt"
+
+	"github.com/chenjianmei111/go-address"
+	acrypto "github.com/chenjianmei111/go-state-types/crypto"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+
+	ffi "github.com/chenjianmei111/filecoin-ffi"
+	"github.com/chenjianmei111/lotus/chain/state"
+	"github.com/chenjianmei111/lotus/chain/types"
+	"github.com/chenjianmei111/lotus/chain/vm"
+	"github.com/chenjianmei111/lotus/lib/blockstore"
+	"github.com/chenjianmei111/lotus/lib/sigs"
+
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// SignedMessage returns the message along with its signature, or nil if the
+// message is not signed.
+func (am *ApplicableMessage) SignedMessage() *types.SignedMessage {
+	if am.Signature == nil {
+		return nil
+	}
+	return &types.SignedMessage{Message: *am.Message, Signature: *am.Signature}
+}
+
+// KeyAddress resolves the supplied address to the pubkey address of the
+// account actor it refers to.
+func (st *StateTracker) KeyAddress(addr address.Address) (address.Address, error) {
+	return vm.ResolveToKeyAddr(st.StateTree, st.Stores.CBORStore, addr)
+}
+
+// sign signs the message as configured by the options it was created with,
+// setting its Signature. Messages are signed right before they are applied or
+// enrolled in a block, so that the signature covers any change made to them
+// after their creation. It does nothing for messages emitted unsigned.
+func (am *ApplicableMessage) sign() {
+	if am.signFn != nil {
+		am.Signature = am.signFn(am.Message)
+	}
+}
+
+// signer returns the function producing the signature messages are to be
+// emitted with, as configured by the supplied options, or nil if they're to
+// be emitted unsigned.
+//
+// The conformance driver executes bare messages, charging secp256k1 senders
+// for a signature only if they're addressed by key; secp256k1 signed messages
+// must therefore be sent from the key address of their sender.
+func (m *Messages) signer(opts msgOpts) func(msg *types.Message) *acrypto.Signature {
+	if opts.signature == nil && !opts.signed {
+		return nil
+	}
+	return func(msg *types.Message) *acrypto.Signature {
+		sig := m.signature(msg, opts)
+		if sig.Type == acrypto.SigTypeSecp256k1 {
+			m.bc.Assert.Equal(address.SECP256K1, msg.From.Protocol(), "secp256k1 signed message must be sent from a key address; got %s", msg.From)
+		}
+		return sig
+	}
+}
+
+// signature signs the supplied message as configured by the supplied options.
+func (m *Messages) signature(msg *types.Message, opts msgOpts) *acrypto.Signature {
+	if opts.signature != nil {
+		sig := *opts.signature
+		return &sig
+	}
+
+	signer := opts.signer
+	if signer == address.Undef {
+		signer = msg.From
+	}
+	key, err := m.st.KeyAddress(signer)
+	m.bc.Assert.NoError(err, "failed to resolve the key address of signer %s", signer)
+
+	sig, err := m.bc.Wallet.Sign(key, msg.Cid().Bytes())
+	m.bc.Assert.NoError(err, "failed to sign message with the key of %s", key)
+
+	if opts.corruptSig {
+		data := make([]byte, len(sig.Data))
+		copy(data, sig.Data)
+		data[len(data)/2] ^= 0xff
+		sig.Data = data
+	}
+	return sig
+}
+
+// assertSignedSelector asserts that the supplied vector, which carries signed
+// messages, declares so through the schema.SelectorSignedMessages selector,
+// for drivers that predate signed messages to skip it.
+func assertSignedSelector(a *Asserter, vector *schema.TestVector) {
+	a.Equal("true", vector.Selector[schema.SelectorSignedMessages], "vectors carrying signed messages must have the %s selector", schema.SelectorSignedMessages)
+}
+
+// VerifyMessageSignature verifies the signature of the supplied message
+// against the key address of its sender, resolved in the state tree with the
+// supplied root.
+func VerifyMessageSignature(bs blockstore.Blockstore, root cid.Cid, smsg *types.SignedMessage) error {
+	cst := cbor.NewCborStore(bs)
+	tree, err := state.LoadStateTree(cst, root)
+	if err != nil {
+		return fmt.Errorf("failed to load state tree: %w", err)
+	}
+	return verifyMessageSignature(tree, cst, smsg)
+}
+
+func verifyMessageSignature(tree *state.StateTree, cst cbor.IpldStore, smsg *types.SignedMessage) error {
+	key, err := vm.ResolveToKeyAddr(tree, cst, smsg.Message.From)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the key address of sender %s: %w", smsg.Message.From, err)
+	}
+	return sigs.Verify(&smsg.Signature, key, smsg.Message.Cid().Bytes())
+}
+
+// ExecutableTipset returns the supplied tipset in the form the conformance
+// driver executes, which only applies the bare messages of blocks.
+//
+// Signed blocks (see schema.Block#Signed) have their signatures verified
+// against the state tree with the supplied root. Those carrying an invalid
+// signature are discarded, like a node would; the messages of the rest are
+// flattened into bare messages, BLS messages first. Unsigned blocks are kept
+// as they are.
+func ExecutableTipset(bs blockstore.Blockstore, root cid.Cid, ts *schema.Tipset) (*schema.Tipset, error) {
+	cst := cbor.NewCborStore(bs)
+	tree, err := state.LoadStateTree(cst, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state tree: %w", err)
+	}
+
+	ret := *ts
+	ret.Blocks = nil
+	for _, blk := range ts.Blocks {
+		if !blk.Signed() {
+			ret.Blocks = append(ret.Blocks, blk)
+			continue
+		}
+		msgs, err := verifyBlockMessages(tree, cst, blk)
+		if err != nil {
+			// the block is invalid; discard it.
+			continue
+		}
+		ret.Blocks = append(ret.Blocks, schema.Block{
+			MinerAddr: blk.MinerAddr,
+			WinCount:  blk.WinCount,
+			Messages:  msgs,
+		})
+	}
+	return &ret, nil
+}
+
+// verifyBlockMessages verifies the BLS aggregate and the secp256k1 signatures
+// of a signed block, and returns its messages, serialized bare, in application
+// order.
+func verifyBlockMessages(tree *state.StateTree, cst cbor.IpldStore, blk schema.Block) ([]schema.Base64EncodedBytes, error) {
+	var (
+		msgs    []schema.Base64EncodedBytes
+		digests []ffi.Digest
+		pubks   []ffi.PublicKey
+	)
+	for i, b := range blk.BLSMessages {
+		msg, err := types.DecodeMessage(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode BLS message %d: %w", i, err)
+		}
+		key, err := vm.ResolveToKeyAddr(tree, cst, msg.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the key address of sender %s: %w", msg.From, err)
+		}
+		if key.Protocol() != address.BLS {
+			return nil, fmt.Errorf("BLS message %d sent by non-BLS account %s", i, key)
+		}
+
+		var pubk ffi.PublicKey
+		copy(pubk[:], key.Payload())
+		pubks = append(pubks, pubk)
+		digests = append(digests, ffi.Hash(msg.Cid().Bytes()))
+		msgs = append(msgs, b)
+	}
+
+	if len(blk.BLSMessages) > 0 {
+		agg := blk.BLSAggregate
+		if agg == nil || agg.Type != schema.SigTypeBLS || len(agg.Data) != ffi.SignatureBytes {
+			return nil, fmt.Errorf("missing or malformed BLS aggregate")
+		}
+		var sig ffi.Signature
+		copy(sig[:], agg.Data)
+		if !ffi.Verify(&sig, digests, pubks) {
+			return nil, fmt.Errorf("invalid BLS aggregate")
+		}
+	}
+
+	for i, b := range blk.SECPMessages {
+		smsg, err := types.DecodeSignedMessage(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secp256k1 message %d: %w", i, err)
+		}
+		if smsg.Signature.Type != acrypto.SigTypeSecp256k1 {
+			return nil, fmt.Errorf("secp256k1 message %d has a signature of type %d", i, smsg.Signature.Type)
+		}
+		if err := verifyMessageSignature(tree, cst, smsg); err != nil {
+			return nil, fmt.Errorf("invalid signature on secp256k1 message %d: %w", i, err)
+		}
+		msgs = append(msgs, MustSerialize(&smsg.Message))
+	}
+	return msgs, nil
+}
+
+// splitSigned splits the supplied signed messages like a block producer does:
+// into BLS messages, which go on chain bare, and secp256k1 messages, which go
+// on chain as SignedMessages.
+func splitSigned(msgs []*ApplicableMessage) (bls, secp []*ApplicableMessage) {
+	for _, am := range msgs {
+		if am.Signature.Type == acrypto.SigTypeBLS {
+			bls = append(bls, am)
+		} else {
+			secp = append(secp, am)
+		}
+	}
+	return bls, secp
+}
+
+// aggregateBLS aggregates the signatures of the supplied BLS messages.
+// Signatures that can't be aggregated, such as corrupted ones, result in an
+// empty aggregate, which fails verification.
+func aggregateBLS(msgs []*ApplicableMessage) *schema.Signature {
+	in := make([]ffi.Signature, len(msgs))
+	for i, am := range msgs {
+		copy(in[i][:], am.Signature.Data)
+	}
+	agg := ffi.Aggregate(in)
+	if agg == nil {
+		zero := ffi.CreateZeroSignature()
+		agg = &zero
+	}
+	return &schema.Signature{Type: schema.SigTypeBLS, Data: agg[:]}
+}
@@ -10,10 +10,6 @@ import (
 	"github.com/chenjianmei111/go-state-types/abi"
 	"github.com/chenjianmei111/lotus/chain/actors"
 	"github.com/chenjianmei111/lotus/chain/actors/adt"
-	miner0 "github.com/chenjianmei111/specs-actors/actors/builtin/miner"
-	adt0 "github.com/chenjianmei111/specs-actors/actors/util/adt"
-	miner2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/miner"
-	adt2 "github.com/chenjianmei111/specs-actors/v2/actors/util/adt"
 	"github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 
@@ -34,6 +30,7 @@ type StateTracker struct {
 
 	StateTreeVersion types.StateTreeVersion
 	ActorsVersion    actors.Version
+	ActorsAdapter    ActorsAdapter
 
 	Stores    *Stores
 	StateTree *state.StateTree
@@ -50,7 +47,7 @@ type StateTracker struct {
 	EmptyVestingFundsCid cid.Cid
 }
 
-func NewStateTracker(bc *BuilderCommon, selector schema.Selector, vector *schema.TestVector, stVersion types.StateTreeVersion, actorsVersion actors.Version, zeroFn ActorsZeroStateFn) *StateTracker {
+func NewStateTracker(bc *BuilderCommon, selector schema.Selector, vector *schema.TestVector, stVersion types.StateTreeVersion, adapter ActorsAdapter) *StateTracker {
 	stores := NewLocalStores(context.Background())
 
 	// create a brand new state tree.
@@ -63,7 +60,8 @@ func NewStateTracker(bc *BuilderCommon, selector schema.Selector, vector *schema
 		vector:           vector,
 		bc:               bc,
 		StateTreeVersion: stVersion,
-		ActorsVersion:    actorsVersion,
+		ActorsVersion:    adapter.Version(),
+		ActorsAdapter:    adapter,
 		Stores:           stores,
 		StateTree:        st,
 		Driver:           conformance.NewDriver(context.Background(), selector, conformance.DriverOpts{}),
@@ -71,7 +69,7 @@ func NewStateTracker(bc *BuilderCommon, selector schema.Selector, vector *schema
 
 	stkr.initEmptyStructures()
 
-	zeroFn(stkr, selector)
+	stkr.ActorsZeroState(selector)
 
 	_ = stkr.Flush()
 	return stkr
@@ -123,59 +121,29 @@ func (st *StateTracker) initEmptyStructures() {
 	}()
 
 	// multimap -- lotus abstraction for version selection does NOT exist.
-	st.EmptyMultiMapCid = func() (ret cid.Cid) {
-		var err error
-		switch st.ActorsVersion {
-		case actors.Version0:
-			ret, err = adt0.MakeEmptyMultimap(st.Stores.ADTStore).Root()
-			if err != nil {
-				panic(err)
-			}
-		case actors.Version2:
-			ret, err = adt2.MakeEmptyMultimap(st.Stores.ADTStore).Root()
-			if err != nil {
-				panic(err)
-			}
-		default:
-			panic("unknown actors version")
+	st.EmptyMultiMapCid = func() cid.Cid {
+		ret, err := st.ActorsAdapter.EmptyMultimap(st)
+		if err != nil {
+			panic(err)
 		}
 		return ret
 	}()
 
-	st.EmptyDeadlinesCid = func() (ret cid.Cid) {
-		var obj cbor.Marshaler
-		switch st.ActorsVersion {
-		case actors.Version0:
-			obj = miner0.ConstructDeadline(st.EmptyArrayCid)
-		case actors.Version2:
-			obj = miner2.ConstructDeadline(st.EmptyArrayCid)
-		default:
-			panic("unknown actors version")
-		}
-		ret, err := st.Stores.ADTStore.Put(context.TODO(), obj)
+	st.EmptyDeadlinesCid = func() cid.Cid {
+		ret, err := st.Stores.ADTStore.Put(context.TODO(), st.ActorsAdapter.EmptyDeadline(st.EmptyArrayCid))
 		if err != nil {
 			panic(err)
 		}
 		return ret
 	}()
 
-	st.EmptyVestingFundsCid = func() (ret cid.Cid) {
-		var obj cbor.Marshaler
-		switch st.ActorsVersion {
-		case actors.Version0:
-			obj = miner0.ConstructVestingFunds()
-		case actors.Version2:
-			obj = miner2.ConstructVestingFunds()
-		default:
-			panic("unknown actors version")
-		}
-		ret, err := st.Stores.ADTStore.Put(context.TODO(), obj)
+	st.EmptyVestingFundsCid = func() cid.Cid {
+		ret, err := st.Stores.ADTStore.Put(context.TODO(), st.ActorsAdapter.EmptyVestingFunds())
 		if err != nil {
 			panic(err)
 		}
 		return ret
 	}()
-
 }
 
 // Fork forks this state tracker into a new one, using the provided cid.Cid as
@@ -218,14 +186,29 @@ func (st *StateTracker) ApplyMessage(am *ApplicableMessage) {
 	var postRoot cid.Cid
 	var err error
 
+	if isInitExec(am.Message) {
+		am.PredictedActor = st.predictExecActor(am.Message)
+	}
+
 	am.baseFee = conformance.BaseFeeOrDefault(st.vector.Pre.BaseFee)
 	am.Applied = true
+
+	// messages with invalid signatures are rejected before execution.
+	am.sign()
+	if smsg := am.SignedMessage(); smsg != nil {
+		if err := verifyMessageSignature(st.StateTree, st.Stores.CBORStore, smsg); err != nil {
+			am.Failed = true
+			return
+		}
+	}
+
 	am.Result, postRoot, err = st.Driver.ExecuteMessage(st.Stores.Blockstore, conformance.ExecuteMessageParams{
 		Preroot:    st.CurrRoot,
 		Epoch:      st.bc.ProtocolVersion.FirstEpoch + am.EpochOffset,
 		Message:    am.Message,
 		BaseFee:    conformance.BaseFeeOrDefault(st.vector.Pre.BaseFee),
 		CircSupply: conformance.CircSupplyOrDefault(st.vector.Pre.CircSupply),
+		Rand:       st.bc.Randomness,
 	})
 	if err != nil {
 		am.Failed = true
@@ -238,6 +221,10 @@ func (st *StateTracker) ApplyMessage(am *ApplicableMessage) {
 	if err != nil {
 		panic(fmt.Sprintf("failed reload state tree after applying message: %s", err))
 	}
+
+	if isInitExec(am.Message) {
+		st.registerExecActor(am)
+	}
 }
 
 // CreateActor creates an actor in the state tree, of the specified kind, with
@@ -280,6 +267,18 @@ func (st *StateTracker) ActorState(addr address.Address, out cbg.CBORUnmarshaler
 	return actor
 }
 
+// UpdateActorState stores the supplied state, and sets it as the head of the
+// supplied actor.
+func (st *StateTracker) UpdateActorState(addr address.Address, state cbor.Marshaler) error {
+	head, err := st.StateTree.Store.Put(context.Background(), state)
+	if err != nil {
+		return err
+	}
+	actor := st.Header(addr)
+	actor.Head = head
+	return st.StateTree.SetActor(addr, actor)
+}
+
 // Header returns the actor's header from the state tree.
 func (st *StateTracker) Header(addr address.Address) *types.Actor {
 	actor, err := st.StateTree.GetActor(addr)
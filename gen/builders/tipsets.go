@@ -32,9 +32,23 @@ type Tipset struct {
 	// state root.
 	PostStateRoot cid.Cid
 
+	// NullRounds are the null rounds immediately preceding this tipset, in
+	// epoch order, populated when applying this tipset.
+	NullRounds []NullRound
+
 	schema.Tipset
 }
 
+// NullRound is an epoch without blocks, in which only cron is executed.
+type NullRound struct {
+	EpochOffset int64
+
+	// PostStateRoot stores the state root CID after the cron execution of
+	// this null round. It can be used with Asserter#AtState to obtain an
+	// asserter against that state root.
+	PostStateRoot cid.Cid
+}
+
 type Block = schema.Block
 
 // NewTipsetSeq returns a new TipSetSeq object initialized at the provided
@@ -83,14 +97,47 @@ func (tss *TipsetSeq) NullRounds(count uint64) {
 // Block adds a new block to this tipset, produced by the indicated miner, with
 // the supplied wincount, and containing the listed (and previously staged)
 // msgIdx.
+//
+// If the messages are signed (see Signed), the block is a signed block: BLS
+// messages are emitted bare, with their signatures aggregated, and precede
+// secp256k1 messages, which are emitted as SignedMessages. Signed and unsigned
+// messages cannot be mixed in a block.
 func (ts *Tipset) Block(miner Miner, winCount int64, msgs ...*ApplicableMessage) {
 	block := Block{
 		MinerAddr: miner.MinerActorAddr.ID,
 		WinCount:  winCount,
 	}
+
+	for _, am := range msgs {
+		am.sign()
+	}
+	signed := len(msgs) > 0 && msgs[0].Signature != nil
 	for _, am := range msgs {
-		block.Messages = append(block.Messages, MustSerialize(am.Message))
+		if (am.Signature != nil) != signed {
+			panic("cannot mix signed and unsigned messages in a block")
+		}
+	}
+
+	if signed {
+		bls, secp := splitSigned(msgs)
+		for _, am := range bls {
+			block.BLSMessages = append(block.BLSMessages, MustSerialize(am.Message))
+		}
+		for _, am := range secp {
+			block.SECPMessages = append(block.SECPMessages, MustSerialize(am.SignedMessage()))
+		}
+		if len(bls) > 0 {
+			block.BLSAggregate = aggregateBLS(bls)
+		}
+		// index messages in application order.
+		msgs = append(bls, secp...)
+	} else {
+		for _, am := range msgs {
+			block.Messages = append(block.Messages, MustSerialize(am.Message))
+		}
+	}
 
+	for _, am := range msgs {
 		// if we see this message for the first time, add it to the `msgIdx` map and to the `orderMsgs` slice.
 		if _, ok := ts.tss.msgIdx[am.Message.Cid()]; !ok {
 			ts.tss.msgIdx[am.Message.Cid()] = am
@@ -2,6 +2,7 @@ package builders
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/chenjianmei111/go-state-types/abi"
 	"github.com/chenjianmei111/go-state-types/network"
@@ -9,8 +10,6 @@ import (
 	"github.com/chenjianmei111/lotus/build"
 	"github.com/chenjianmei111/lotus/chain/actors"
 	"github.com/chenjianmei111/lotus/chain/types"
-
-	"github.com/chenjianmei111/test-vectors/schema"
 )
 
 // ProtocolVersion represents a protocol upgrade we track.
@@ -29,69 +28,65 @@ type ProtocolVersion struct {
 	// StateTree is the state tree version.
 	StateTree types.StateTreeVersion
 
-	// Actors is the actors version.
+	// Actors is the actors version. The ActorsAdapter registered for it
+	// constructs the initial state tree, including singleton system actors.
 	Actors actors.Version
+}
 
-	// ZeroStateTree is the constructor of the initial state tree, including
-	// singleton system actors.
-	ZeroStateTree func(*StateTracker, schema.Selector)
+// ActorsAdapter returns the ActorsAdapter registered for the actors version
+// of this protocol version.
+func (pv ProtocolVersion) ActorsAdapter() ActorsAdapter {
+	return GetActorsAdapter(pv.Actors)
 }
 
 // KnownProtocolVersions enumerates the protocol versions we're capable of
 // generating test vectors against.
 var KnownProtocolVersions = []ProtocolVersion{
 	{
-		ID:            "genesis",
-		FirstEpoch:    1,
-		StateTree:     types.StateTreeVersion0,
-		Network:       network.Version0,
-		Actors:        actors.Version0,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV0,
+		ID:         "genesis",
+		FirstEpoch: 1,
+		StateTree:  types.StateTreeVersion0,
+		Network:    network.Version0,
+		Actors:     actors.Version0,
 	},
 	{
-		ID:            "breeze",
-		FirstEpoch:    build.UpgradeBreezeHeight + 1,
-		StateTree:     types.StateTreeVersion0,
-		Network:       network.Version1,
-		Actors:        actors.Version0,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV0,
+		ID:         "breeze",
+		FirstEpoch: build.UpgradeBreezeHeight + 1,
+		StateTree:  types.StateTreeVersion0,
+		Network:    network.Version1,
+		Actors:     actors.Version0,
 	}, {
-		ID:            "smoke",
-		FirstEpoch:    build.UpgradeSmokeHeight + 1,
-		StateTree:     types.StateTreeVersion0,
-		Network:       network.Version2,
-		Actors:        actors.Version0,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV0,
+		ID:         "smoke",
+		FirstEpoch: build.UpgradeSmokeHeight + 1,
+		StateTree:  types.StateTreeVersion0,
+		Network:    network.Version2,
+		Actors:     actors.Version0,
 	}, {
-		ID:            "ignition",
-		FirstEpoch:    build.UpgradeIgnitionHeight + 1,
-		StateTree:     types.StateTreeVersion0,
-		Network:       network.Version3,
-		Actors:        actors.Version0,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV0,
+		ID:         "ignition",
+		FirstEpoch: build.UpgradeIgnitionHeight + 1,
+		StateTree:  types.StateTreeVersion0,
+		Network:    network.Version3,
+		Actors:     actors.Version0,
 	}, {
-		ID:            "actorsv2",
-		FirstEpoch:    build.UpgradeActorsV2Height + 1,
-		StateTree:     types.StateTreeVersion1,
-		Network:       network.Version4,
-		Actors:        actors.Version2,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV2,
+		ID:         "actorsv2",
+		FirstEpoch: build.UpgradeActorsV2Height + 1,
+		StateTree:  types.StateTreeVersion1,
+		Network:    network.Version4,
+		Actors:     actors.Version2,
 	},
 	{
-		ID:            "tape",
-		FirstEpoch:    build.UpgradeTapeHeight + 1,
-		StateTree:     types.StateTreeVersion1,
-		Network:       network.Version5,
-		Actors:        actors.Version2,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV2,
+		ID:         "tape",
+		FirstEpoch: build.UpgradeTapeHeight + 1,
+		StateTree:  types.StateTreeVersion1,
+		Network:    network.Version5,
+		Actors:     actors.Version2,
 	},
 	{
-		ID:            "liftoff",
-		FirstEpoch:    build.UpgradeLiftoffHeight + 1,
-		StateTree:     types.StateTreeVersion1,
-		Network:       network.Version5,
-		Actors:        actors.Version2,
-		ZeroStateTree: (*StateTracker).ActorsZeroStateV2,
+		ID:         "liftoff",
+		FirstEpoch: build.UpgradeLiftoffHeight + 1,
+		StateTree:  types.StateTreeVersion1,
+		Network:    network.Version5,
+		Actors:     actors.Version2,
 	},
 }
 
@@ -134,3 +129,82 @@ func KnownProtocolVersionsBetween(from, to string) []ProtocolVersion {
 	}
 	return KnownProtocolVersions[start : end+1]
 }
+
+// KnownProtocolVersionsOf returns the known protocol versions with the
+// supplied IDs, in the order they are known.
+func KnownProtocolVersionsOf(ids ...string) []ProtocolVersion {
+	var ret []ProtocolVersion
+	for _, pv := range KnownProtocolVersions {
+		for _, id := range ids {
+			if pv.ID == id {
+				ret = append(ret, pv)
+			}
+		}
+	}
+	if len(ret) != len(ids) {
+		panic(fmt.Sprintf("at least one unknown protocol version: %s", strings.Join(ids, ", ")))
+	}
+	return ret
+}
+
+// ParseProtocolVersions parses a comma-separated list of protocol version
+// IDs, returning the set of IDs it denotes. Besides plain IDs, terms can be
+// inclusive ranges of known protocol versions:
+//
+//   - "smoke..actorsv2": from smoke to actorsv2.
+//   - "actorsv2..": from actorsv2 onwards.
+//   - "..smoke": up to smoke.
+func ParseProtocolVersions(spec string) (map[string]struct{}, error) {
+	indexOf := func(id string) (int, error) {
+		for i, pv := range KnownProtocolVersions {
+			if pv.ID == id {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("unknown protocol version: %s", id)
+	}
+
+	ret := make(map[string]struct{})
+	for _, term := range strings.Split(spec, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var (
+			start, end int
+			err        error
+		)
+		if i := strings.Index(term, ".."); i == -1 {
+			if start, err = indexOf(term); err != nil {
+				return nil, err
+			}
+			end = start
+		} else {
+			from, to := term[:i], term[i+2:]
+			start, end = 0, len(KnownProtocolVersions)-1
+			if from != "" {
+				if start, err = indexOf(from); err != nil {
+					return nil, err
+				}
+			}
+			if to != "" {
+				if end, err = indexOf(to); err != nil {
+					return nil, err
+				}
+			}
+			if start > end {
+				return nil, fmt.Errorf("invalid protocol version range: %s", term)
+			}
+		}
+
+		for _, pv := range KnownProtocolVersions[start : end+1] {
+			ret[pv.ID] = struct{}{}
+		}
+	}
+
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no protocol versions selected by: %q", spec)
+	}
+	return ret, nil
+}
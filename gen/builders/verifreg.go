@@ -0,0 +1,53 @@
+package builders
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/verifreg"
+
+	builtin0 "github.com/chenjianmei111/specs-actors/actors/builtin"
+)
+
+// VerifregState loads the state of the verified registry actor.
+func (st *StateTracker) VerifregState() verifreg.State {
+	actor := st.Header(builtin0.VerifiedRegistryActorAddr)
+	vs, err := verifreg.Load(st.Stores.ADTStore, actor)
+	st.bc.Assert.NoError(err, "failed to load the verified registry actor state")
+	return vs
+}
+
+// VerifierDataCapEq verifies that the address is a verifier, with a remaining
+// allowance equal to the expected one. The address must be an ID address.
+func (a *Asserter) VerifierDataCapEq(addr address.Address, expected abi.StoragePower) {
+	found, actual, err := a.suppliers.stateTracker().VerifregState().VerifierDataCap(addr)
+	a.NoError(err, "failed to get the datacap of verifier %s", addr)
+	a.True(found, "%s is not a verifier", addr)
+	a.Equal(expected, actual, "datacaps mismatch for verifier %s", addr)
+}
+
+// VerifierMissing verifies that the address is not a verifier. The address
+// must be an ID address.
+func (a *Asserter) VerifierMissing(addr address.Address) {
+	found, _, err := a.suppliers.stateTracker().VerifregState().VerifierDataCap(addr)
+	a.NoError(err, "failed to get the datacap of verifier %s", addr)
+	a.False(found, "%s is unexpectedly a verifier", addr)
+}
+
+// VerifiedClientDataCapEq verifies that the address is a verified client, with
+// a remaining datacap equal to the expected one. The address must be an ID
+// address.
+func (a *Asserter) VerifiedClientDataCapEq(addr address.Address, expected abi.StoragePower) {
+	found, actual, err := a.suppliers.stateTracker().VerifregState().VerifiedClientDataCap(addr)
+	a.NoError(err, "failed to get the datacap of verified client %s", addr)
+	a.True(found, "%s is not a verified client", addr)
+	a.Equal(expected, actual, "datacaps mismatch for verified client %s", addr)
+}
+
+// VerifiedClientMissing verifies that the address is not a verified client.
+// The address must be an ID address.
+func (a *Asserter) VerifiedClientMissing(addr address.Address) {
+	found, _, err := a.suppliers.stateTracker().VerifregState().VerifiedClientDataCap(addr)
+	a.NoError(err, "failed to get the datacap of verified client %s", addr)
+	a.False(found, "%s is unexpectedly a verified client", addr)
+}
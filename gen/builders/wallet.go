@@ -1,9 +1,13 @@
 package builders
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 
+	ffi "github.com/chenjianmei111/filecoin-ffi"
+
 	"github.com/chenjianmei111/lotus/chain/types"
 	"github.com/chenjianmei111/lotus/chain/wallet"
 	"github.com/chenjianmei111/lotus/lib/sigs"
@@ -20,9 +24,14 @@ type Wallet struct {
 	// Seed for deterministic secp key generation.
 	secpSeed int64
 	// Seed for deterministic bls key generation.
-	blsSeed int64 // nolint: structcheck
+	blsSeed int64
+	// legacyBLS selects the legacy bls key derivation; see UseLegacyBLSKeys.
+	legacyBLS bool
 }
 
+// blsKeyDomain separates the seeds of bls keys from those of other keys.
+const blsKeyDomain = "test-vectors/wallet/bls/"
+
 func NewWallet() *Wallet {
 	return &Wallet{
 		keys:     make(map[address.Address]*wallet.Key),
@@ -69,16 +78,38 @@ func (w *Wallet) newSecp256k1Key() *wallet.Key {
 	return key
 }
 
+// UseLegacyBLSKeys switches the wallet to the legacy bls key derivation, which
+// sets the first byte of the private key to the seed. It yields degenerate
+// keys, and wraps around after 255 accounts. It only exists so that vectors
+// generated before the seeded derivation keep their content; new vectors must
+// not use it. It must be called before creating any bls account.
+func (w *Wallet) UseLegacyBLSKeys() {
+	for _, k := range w.keys {
+		if k.Type == types.KTBLS {
+			panic("UseLegacyBLSKeys must be called before creating any bls account")
+		}
+	}
+	w.legacyBLS = true
+}
+
 func (w *Wallet) newBLSKey() *wallet.Key {
-	// FIXME: bls needs deterministic key generation
-	//sk := ffi.PrivateKeyGenerate(s.blsSeed)
-	// s.blsSeed++
-	var sk [32]byte
-	sk[0] = uint8(w.blsSeed) // hack to keep gas values determinist
+	var sk []byte
+	if w.legacyBLS {
+		var legacy [32]byte
+		legacy[0] = uint8(w.blsSeed)
+		sk = legacy[:]
+	} else {
+		// derive the key generation seed from the wallet seed.
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(w.blsSeed))
+		seed := sha256.Sum256(append([]byte(blsKeyDomain), n[:]...))
+		prv := ffi.PrivateKeyGenerateWithSeed(ffi.PrivateKeyGenSeed(seed))
+		sk = prv[:]
+	}
 	w.blsSeed++
 	key, err := wallet.NewKey(types.KeyInfo{
 		Type:       types.KTBLS,
-		PrivateKey: sk[:],
+		PrivateKey: sk,
 	})
 	if err != nil {
 		panic(err)
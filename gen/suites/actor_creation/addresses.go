@@ -9,7 +9,6 @@ import (
 	. "github.com/chenjianmei111/test-vectors/gen/builders"
 
 	"github.com/chenjianmei111/go-state-types/big"
-	init_ "github.com/chenjianmei111/specs-actors/actors/builtin/init"
 
 	"github.com/chenjianmei111/go-address"
 )
@@ -40,11 +39,10 @@ func sequentialAddresses(v *MessageVectorBuilder) {
 			Robust: sender.NextActorAddress(am.Message.Nonce, 0),
 		}
 
-		// Verify that the return contains the expected addresses.
-		var ret init_.ExecReturn
-		MustDeserialize(am.Result.Return, &ret)
-		v.Assert.Equal(expectedActorAddr.Robust, ret.RobustAddress)
-		v.Assert.Equal(expectedActorAddr.ID, ret.IDAddress)
+		// Verify that the return contains the expected addresses, which are
+		// those the builder predicted.
+		v.Assert.NoError(ExecReturns(expectedActorAddr)(am.Result))
+		v.Assert.ExecReturnsPredicted(am)
 	}
 
 	v.Assert.EveryMessageSenderSatisfies(BalanceUpdated(big.Zero()))
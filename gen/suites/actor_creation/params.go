@@ -21,10 +21,9 @@ func createActorInitExecUnparsableParams(v *MessageVectorBuilder) {
 	v.CommitPreconditions()
 
 	// Valid message for construction of a payment channel.
-	createMsg := v.Messages.Typed(sender.ID, builtin.InitActorAddr, InitExec(&init_.ExecParams{
-		CodeCID:           builtin.PaymentChannelActorCodeID,
-		ConstructorParams: MustSerialize(&paych.ConstructorParams{From: sender.ID, To: receiver.ID}),
-	}), Value(abi.NewTokenAmount(10_000)), Nonce(0))
+	createMsg := v.Messages.Typed(sender.ID, builtin.InitActorAddr, InitExec(PaymentChannelActor,
+		&paych.ConstructorParams{From: sender.ID, To: receiver.ID},
+	), Value(abi.NewTokenAmount(10_000)), Nonce(0))
 
 	// mangle the InitExec params to form an invalid CBOR payload.
 	createMsg.Message.Params = append([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, createMsg.Message.Params...)
@@ -50,7 +49,7 @@ func createActorCtorUnparsableParamsViaInitExec(v *MessageVectorBuilder) {
 	ctorparams = append([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, ctorparams...)
 
 	// Valid message for construction of a payment channel.
-	v.Messages.Typed(sender.ID, builtin.InitActorAddr, InitExec(&init_.ExecParams{
+	v.Messages.Typed(sender.ID, builtin.InitActorAddr, InitExecRaw(&init_.ExecParams{
 		CodeCID:           builtin.PaymentChannelActorCodeID,
 		ConstructorParams: ctorparams,
 	}), Value(abi.NewTokenAmount(10_000)), Nonce(0))
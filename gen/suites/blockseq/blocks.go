@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+	"github.com/chenjianmei111/lotus/chain/types"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+func setupMiners(v *BlockSeqVectorBuilder) (minerA, minerB Miner) {
+	v.SetInitialEpochOffset(1)
+
+	cfg := MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: 1000,
+		OwnerBalance:   balance,
+	}
+	v.Actors.MinerN(cfg, &minerA, &minerB)
+	v.CommitPreconditions()
+
+	v.StagedMessages.SetDefaults(GasLimit(1_000_000_000), GasPremium(0), GasFeeCap(200))
+	return minerA, minerB
+}
+
+func validBlocksAccepted(v *BlockSeqVectorBuilder) {
+	minerA, minerB := setupMiners(v)
+
+	transfer1 := v.StagedMessages.Sugar().Transfer(minerA.OwnerAddr.Robust, minerB.OwnerAddr.ID, Value(abi.NewTokenAmount(1)), Nonce(0))
+	transfer2 := v.StagedMessages.Sugar().Transfer(minerA.OwnerAddr.Robust, minerB.OwnerAddr.ID, Value(abi.NewTokenAmount(1)), Nonce(1))
+
+	e1 := v.Blocks.Next(abi.NewTokenAmount(100))
+	e1.Block(minerA, 1, transfer1)
+	e1.Block(minerB, 1, transfer1)
+
+	e2 := v.Blocks.Next(abi.NewTokenAmount(100))
+	e2.Block(minerA, 1, transfer2)
+
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.EveryMessageSenderSatisfies(BalanceUpdated(big.Zero()))
+}
+
+func rejectBadBlockSignature(v *BlockSeqVectorBuilder) {
+	minerA, minerB := setupMiners(v)
+
+	transfer1 := v.StagedMessages.Sugar().Transfer(minerA.OwnerAddr.Robust, minerB.OwnerAddr.ID, Value(abi.NewTokenAmount(1)), Nonce(0))
+	transfer2 := v.StagedMessages.Sugar().Transfer(minerA.OwnerAddr.Robust, minerB.OwnerAddr.ID, Value(abi.NewTokenAmount(1)), Nonce(1))
+
+	e1 := v.Blocks.Next(abi.NewTokenAmount(100))
+	e1.Block(minerA, 1, transfer1)
+	e1.Block(minerB, 1, transfer2).
+		TamperSigned(func(h *types.BlockHeader) {
+			h.BlockSig.Data[0] ^= 0xff
+		}).
+		ExpectReject("block signature is invalid")
+
+	v.CommitApplies()
+
+	// only the message in the accepted block was applied.
+	v.Assert.Len(v.Blocks.Messages(), 1)
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.NonceEq(minerA.OwnerAddr.Robust, 1)
+}
+
+func rejectWrongParentStateRoot(v *BlockSeqVectorBuilder) {
+	minerA, minerB := setupMiners(v)
+
+	transfer1 := v.StagedMessages.Sugar().Transfer(minerA.OwnerAddr.Robust, minerB.OwnerAddr.ID, Value(abi.NewTokenAmount(1)), Nonce(0))
+	transfer2 := v.StagedMessages.Sugar().Transfer(minerA.OwnerAddr.Robust, minerB.OwnerAddr.ID, Value(abi.NewTokenAmount(1)), Nonce(1))
+
+	e1 := v.Blocks.Next(abi.NewTokenAmount(100))
+	e1.Block(minerA, 1, transfer1)
+
+	// the second block claims the state root prior to executing the first
+	// epoch as its parent state root.
+	e2 := v.Blocks.Next(abi.NewTokenAmount(100))
+	e2.Block(minerB, 1, transfer2).
+		Tamper(func(h *types.BlockHeader) {
+			h.ParentStateRoot = v.PreRoot
+		}).
+		ExpectReject("parent state root does not match the computed state")
+
+	v.CommitApplies()
+
+	v.Assert.Len(v.Blocks.Messages(), 1)
+	v.Assert.NonceEq(minerA.OwnerAddr.Robust, 1)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+var (
+	balance = abi.NewTokenAmount(1_000_000_000_000_000)
+)
+
+func main() {
+	g := NewGenerator()
+	defer g.Close()
+
+	g.Group("blocks",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-valid-blocks-accepted",
+				Version: "v1",
+				Desc:    "verifies that well-formed blocks from multiple miners across epochs are accepted and executed",
+			},
+			Selector:     map[string]string{"signed_messages": "true"},
+			BlockSeqFunc: validBlocksAccepted,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "reject-bad-block-signature",
+				Version: "v1",
+				Desc:    "verifies that a block with a corrupted signature is rejected, while its valid sibling is accepted",
+			},
+			Selector:     map[string]string{"signed_messages": "true"},
+			BlockSeqFunc: rejectBadBlockSignature,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "reject-wrong-parent-state-root",
+				Version: "v1",
+				Desc:    "verifies that a block declaring a parent state root that doesn't match the computed state is rejected",
+			},
+			Selector:     map[string]string{"signed_messages": "true"},
+			BlockSeqFunc: rejectWrongParentStateRoot,
+		},
+	)
+}
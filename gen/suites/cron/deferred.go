@@ -0,0 +1,138 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/specs-actors/actors/builtin/miner"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+// setup creates the supplied miners, with a proving period starting at the
+// epoch of the preconditions, and enrolls a proving deadline event for each of
+// them, in order, at the last epoch of their first deadline. It returns the
+// epoch of the preconditions, which tipset epoch offsets are relative to, and
+// the epoch of the events.
+func setup(v *TipsetVectorBuilder, miners ...*Miner) (base, eventEpoch abi.ChainEpoch) {
+	v.SetInitialEpochOffset(1)
+	base = v.ProtocolVersion.FirstEpoch + v.InitialEpochOffset
+
+	v.Actors.MinerN(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: base,
+		OwnerBalance:   balance,
+	}, miners...)
+
+	eventEpoch = base + miner.WPoStChallengeWindow - 1
+	for _, m := range miners {
+		v.Actors.EnrollCronEvent(*m, eventEpoch, v.Actors.ProvingDeadlineCronPayload())
+	}
+	v.CommitPreconditions()
+	return base, eventEpoch
+}
+
+// deadlineEvent builds a proving deadline event for the supplied miner
+// and epoch.
+func deadlineEvent(v *TipsetVectorBuilder, m Miner, epoch abi.ChainEpoch) CronEvent {
+	return CronEvent{Epoch: epoch, Miner: m.MinerActorAddr.ID, Payload: v.Actors.ProvingDeadlineCronPayload()}
+}
+
+func provingDeadlineEvent(v *TipsetVectorBuilder) {
+	var minerA Miner
+	base, epoch := setup(v, &minerA)
+
+	// tipsets right before, at, and right after the event epoch; the tipset
+	// sequence starts at offset 1.
+	v.Tipsets.NullRounds(uint64(epoch - base - 2))
+	before := v.Tipsets.Next(baseFee)
+	before.Block(minerA, 1)
+	at := v.Tipsets.Next(baseFee)
+	at.Block(minerA, 1)
+	after := v.Tipsets.Next(baseFee)
+	after.Block(minerA, 1)
+
+	v.CommitApplies()
+
+	v.Assert.EqualValues(epoch-base, at.EpochOffset)
+
+	// the event is pending until its epoch.
+	v.Assert.AtState(before.PostStateRoot).CronEventQueueEq(deadlineEvent(v, minerA, epoch))
+	v.Assert.AtState(before.PostStateRoot).MinerDeadlineEq(minerA.MinerActorAddr.ID, epoch-1, base, 0)
+
+	// the miner handled the event at its epoch, and re-enrolled for the last
+	// epoch of its next deadline.
+	next := epoch + miner.WPoStChallengeWindow
+	v.Assert.AtState(at.PostStateRoot).CronEventQueueEq(deadlineEvent(v, minerA, next))
+	v.Assert.AtState(at.PostStateRoot).MinerDeadlineEq(minerA.MinerActorAddr.ID, epoch, base, 1)
+
+	// nothing else happens until the next event.
+	v.Assert.CronEventQueueEq(deadlineEvent(v, minerA, next))
+	v.Assert.MinerDeadlineEq(minerA.MinerActorAddr.ID, epoch+1, base, 1)
+}
+
+func eventDuringNullRounds(v *TipsetVectorBuilder) {
+	var minerA Miner
+	base, epoch := setup(v, &minerA)
+
+	// a tipset at the first epoch, and another one after the event epoch,
+	// with null rounds in between.
+	first := v.Tipsets.Next(baseFee)
+	first.Block(minerA, 1)
+	v.Tipsets.NullRounds(uint64(epoch - base))
+	last := v.Tipsets.Next(baseFee)
+	last.Block(minerA, 1)
+
+	v.CommitApplies()
+
+	// find the null rounds right before and at the event epoch.
+	var before, at *NullRound
+	for i := range last.NullRounds {
+		switch nr := &last.NullRounds[i]; nr.EpochOffset {
+		case int64(epoch - base - 1):
+			before = nr
+		case int64(epoch - base):
+			at = nr
+		}
+	}
+	v.Assert.NotNil(before, "no null round before the event epoch")
+	v.Assert.NotNil(at, "no null round at the event epoch")
+
+	next := epoch + miner.WPoStChallengeWindow
+	v.Assert.AtState(before.PostStateRoot).CronEventQueueEq(deadlineEvent(v, minerA, epoch))
+	v.Assert.AtState(before.PostStateRoot).MinerDeadlineEq(minerA.MinerActorAddr.ID, epoch-1, base, 0)
+	v.Assert.AtState(at.PostStateRoot).CronEventQueueEq(deadlineEvent(v, minerA, next))
+	v.Assert.AtState(at.PostStateRoot).MinerDeadlineEq(minerA.MinerActorAddr.ID, epoch, base, 1)
+
+	// the event is not delivered again by the following tipset.
+	v.Assert.CronEventQueueEq(deadlineEvent(v, minerA, next))
+	v.Assert.MinerDeadlineEq(minerA.MinerActorAddr.ID, base+abi.ChainEpoch(last.EpochOffset), base, 1)
+}
+
+func sameEpochEventsOrder(v *TipsetVectorBuilder) {
+	var minerA, minerB, minerC Miner
+	base, epoch := setup(v, &minerA, &minerB, &minerC)
+
+	v.Tipsets.NullRounds(uint64(epoch - base - 2))
+	before := v.Tipsets.Next(baseFee)
+	before.Block(minerA, 1)
+	at := v.Tipsets.Next(baseFee)
+	at.Block(minerB, 1)
+
+	v.CommitApplies()
+
+	v.Assert.AtState(before.PostStateRoot).CronEventQueueEq(
+		deadlineEvent(v, minerA, epoch),
+		deadlineEvent(v, minerB, epoch),
+		deadlineEvent(v, minerC, epoch),
+	)
+
+	// every miner handled its event, and re-enrolled in delivery order.
+	next := epoch + miner.WPoStChallengeWindow
+	v.Assert.CronEventQueueEq(
+		deadlineEvent(v, minerA, next),
+		deadlineEvent(v, minerB, next),
+		deadlineEvent(v, minerC, next),
+	)
+	for _, m := range []Miner{minerA, minerB, minerC} {
+		v.Assert.MinerDeadlineEq(m.MinerActorAddr.ID, epoch, base, 1)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+var (
+	balance = abi.NewTokenAmount(1_000_000_000_000_000)
+	baseFee = abi.NewTokenAmount(100)
+)
+
+func main() {
+	g := NewGenerator()
+	defer g.Close()
+
+	g.Group("deferred",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-proving-deadline-event",
+				Version: "v1",
+				Desc:    "a proving deadline event enrolled in the power actor is delivered to the miner at its epoch; the miner advances its deadline and re-enrolls for the end of the next one",
+			},
+			TipsetFunc: provingDeadlineEvent,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-event-during-null-rounds",
+				Version: "v1",
+				Desc:    "a proving deadline event whose epoch is a null round is delivered by the cron execution of that null round",
+			},
+			TipsetFunc: eventDuringNullRounds,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-same-epoch-events-in-enrollment-order",
+				Version: "v1",
+				Desc:    "events enrolled by several miners for the same epoch are delivered in enrollment order; the re-enrolled events keep that order",
+			},
+			TipsetFunc: sameEpochEventsOrder,
+		},
+	)
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/market"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+// parties sets up a client account, and a provider miner whose worker is a
+// funded account, so that it can send messages.
+func parties(v *MessageVectorBuilder) (client AddressHandle, provider Miner) {
+	client = v.Actors.Account(address.SECP256K1, balance)
+	worker := v.Actors.Account(address.BLS, balance)
+	provider = v.Actors.Miner(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: 0,
+		OwnerBalance:   balance,
+		Worker:         &worker,
+	})
+	return client, provider
+}
+
+// dealProposal returns a deal proposal between the client and the provider,
+// starting 100 epochs after the vector epoch.
+func dealProposal(v *MessageVectorBuilder, client AddressHandle, provider Miner, n int) market0.DealProposal {
+	return dealProposalAt(v.ProtocolVersion.FirstEpoch+100, client, provider, n)
+}
+
+// dealProposalAt returns a deal proposal between the client and the provider,
+// starting at the supplied epoch.
+func dealProposalAt(start abi.ChainEpoch, client AddressHandle, provider Miner, n int) market0.DealProposal {
+	return market0.DealProposal{
+		PieceCID:             MustNewPieceCID(fmt.Sprintf("piece-%d", n)),
+		PieceSize:            abi.PaddedPieceSize(2048),
+		Client:               client.ID,
+		Provider:             provider.MinerActorAddr.ID,
+		Label:                fmt.Sprintf("deal-%d", n),
+		StartEpoch:           start,
+		EndEpoch:             start + dealDuration,
+		StoragePricePerEpoch: dealPrice,
+		ProviderCollateral:   providerCollateral,
+		ClientCollateral:     clientCollateral,
+	}
+}
+
+// signed signs the deal proposal with the key of the signer.
+func signed(v *MessageVectorBuilder, signer address.Address, proposal market0.DealProposal) market0.ClientDealProposal {
+	cdp, err := v.Wallet.SignDealProposal(signer, proposal)
+	v.Assert.NoError(err, "failed to sign deal proposal")
+	return *cdp
+}
+
+// escrow escrows the funds required by the deal for both parties, and
+// returns the nonce the worker is at.
+func escrow(v *MessageVectorBuilder, client AddressHandle, provider Miner, proposal market0.DealProposal) uint64 {
+	v.Messages.Sugar().AddMarketBalance(client.Robust, client.ID, proposal.ClientBalanceRequirement(), Nonce(0))
+	v.Messages.Sugar().AddMarketBalance(provider.WorkerAddr.Robust, provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement(), Nonce(0))
+	return 1
+}
+
+func publishDealOk(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	client, provider := parties(v)
+	v.CommitPreconditions()
+
+	proposal := dealProposal(v, client, provider, 0)
+	nonce := escrow(v, client, provider, proposal)
+	publish := v.Messages.Sugar().PublishStorageDeals(provider.WorkerAddr.Robust, []market0.ClientDealProposal{signed(v, client.Robust, proposal)}, Nonce(nonce))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+
+	var ret market0.PublishStorageDealsReturn
+	MustDeserialize(publish.Result.Return, &ret)
+	v.Assert.Equal([]abi.DealID{0}, ret.IDs)
+
+	// the deal is published, but not active.
+	v.Assert.DealProposalEq(0, proposal)
+	v.Assert.DealStateMissing(0)
+
+	// the escrowed funds of both parties are locked.
+	v.Assert.MarketEscrowEq(client.ID, proposal.ClientBalanceRequirement())
+	v.Assert.MarketLockedEq(client.ID, proposal.ClientBalanceRequirement())
+	v.Assert.MarketEscrowEq(provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement())
+	v.Assert.MarketLockedEq(provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement())
+}
+
+func publishDealInvalidSignature(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	client, provider := parties(v)
+	v.CommitPreconditions()
+
+	// the proposal is signed by the worker instead of the client.
+	proposal := dealProposal(v, client, provider, 0)
+	nonce := escrow(v, client, provider, proposal)
+	publish := v.Messages.Sugar().PublishStorageDeals(provider.WorkerAddr.Robust, []market0.ClientDealProposal{signed(v, provider.WorkerAddr.Robust, proposal)}, Nonce(nonce))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok), publish)
+	v.Assert.ExitCodeEq(publish.Result.ExitCode, exitcode.ErrIllegalArgument)
+
+	v.Assert.DealProposalMissing(0)
+	v.Assert.MarketLockedEq(client.ID, big.Zero())
+	v.Assert.MarketLockedEq(provider.MinerActorAddr.ID, big.Zero())
+}
+
+// publishDealInsufficientFunds returns a builder of a vector where either the
+// client or, if providerShort is true, the provider escrows one attoFIL less
+// than the deal requires.
+func publishDealInsufficientFunds(providerShort bool) func(v *MessageVectorBuilder) {
+	return func(v *MessageVectorBuilder) {
+		v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+		client, provider := parties(v)
+		v.CommitPreconditions()
+
+		proposal := dealProposal(v, client, provider, 0)
+		clientFunds, providerFunds := proposal.ClientBalanceRequirement(), proposal.ProviderBalanceRequirement()
+		if providerShort {
+			providerFunds = big.Sub(providerFunds, big.NewInt(1))
+		} else {
+			clientFunds = big.Sub(clientFunds, big.NewInt(1))
+		}
+
+		v.Messages.Sugar().AddMarketBalance(client.Robust, client.ID, clientFunds, Nonce(0))
+		v.Messages.Sugar().AddMarketBalance(provider.WorkerAddr.Robust, provider.MinerActorAddr.ID, providerFunds, Nonce(0))
+		publish := v.Messages.Sugar().PublishStorageDeals(provider.WorkerAddr.Robust, []market0.ClientDealProposal{signed(v, client.Robust, proposal)}, Nonce(1))
+		v.CommitApplies()
+
+		v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok), publish)
+		v.Assert.ExitCodeEq(publish.Result.ExitCode, exitcode.ErrInsufficientFunds)
+
+		v.Assert.DealProposalMissing(0)
+		v.Assert.MarketEscrowEq(client.ID, clientFunds)
+		v.Assert.MarketLockedEq(client.ID, big.Zero())
+		v.Assert.MarketEscrowEq(provider.MinerActorAddr.ID, providerFunds)
+		v.Assert.MarketLockedEq(provider.MinerActorAddr.ID, big.Zero())
+	}
+}
+
+func publishDealNotWorker(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	client, provider := parties(v)
+	v.CommitPreconditions()
+
+	// the client publishes the deal.
+	proposal := dealProposal(v, client, provider, 0)
+	escrow(v, client, provider, proposal)
+	publish := v.Messages.Sugar().PublishStorageDeals(client.Robust, []market0.ClientDealProposal{signed(v, client.Robust, proposal)}, Nonce(1))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok), publish)
+	v.Assert.ExitCodeEq(publish.Result.ExitCode, exitcode.ErrForbidden)
+
+	v.Assert.DealProposalMissing(0)
+	v.Assert.MarketLockedEq(client.ID, big.Zero())
+}
+
+func activateDealNotMiner(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	client, provider := parties(v)
+	v.CommitPreconditions()
+
+	proposal := dealProposal(v, client, provider, 0)
+	nonce := escrow(v, client, provider, proposal)
+	v.Messages.Sugar().PublishStorageDeals(provider.WorkerAddr.Robust, []market0.ClientDealProposal{signed(v, client.Robust, proposal)}, Nonce(nonce))
+
+	// both the worker and the owner of the provider attempt to activate the
+	// deal directly.
+	params := &market0.ActivateDealsParams{DealIDs: []abi.DealID{0}, SectorExpiry: proposal.EndEpoch}
+	byWorker := v.Messages.Typed(provider.WorkerAddr.Robust, builtin.StorageMarketActorAddr, MarketActivateDeals(params), Nonce(nonce+1))
+	byOwner := v.Messages.Typed(provider.OwnerAddr.Robust, builtin.StorageMarketActorAddr, MarketActivateDeals(params), Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok), byWorker, byOwner)
+	v.Assert.ExitCodeEq(byWorker.Result.ExitCode, exitcode.SysErrForbidden)
+	v.Assert.ExitCodeEq(byOwner.Result.ExitCode, exitcode.SysErrForbidden)
+
+	// the deal is still published, but not active.
+	v.Assert.DealProposalEq(0, proposal)
+	v.Assert.DealStateMissing(0)
+}
+
+func withdrawUnlockedBalance(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	client, provider := parties(v)
+	v.CommitPreconditions()
+
+	// the client escrows more than the deal requires.
+	var (
+		proposal = dealProposal(v, client, provider, 0)
+		required = proposal.ClientBalanceRequirement()
+		extra    = abi.NewTokenAmount(1_000_000)
+		escrowed = big.Add(required, extra)
+	)
+	deposit := v.Messages.Sugar().AddMarketBalance(client.Robust, client.ID, escrowed, Nonce(0))
+	v.Messages.Sugar().AddMarketBalance(provider.WorkerAddr.Robust, provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement(), Nonce(0))
+	v.Messages.Sugar().PublishStorageDeals(provider.WorkerAddr.Robust, []market0.ClientDealProposal{signed(v, client.Robust, proposal)}, Nonce(1))
+
+	// the client attempts to withdraw all its escrow; only the extra is
+	// withdrawn.
+	withdraw := v.Messages.Sugar().WithdrawMarketBalance(client.Robust, client.ID, escrowed, Nonce(1))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.MarketEscrowEq(client.ID, required)
+	v.Assert.MarketLockedEq(client.ID, required)
+
+	// the client balance is debited the deposit, and credited the extra.
+	v.Assert.MessageSendersSatisfy(BalanceUpdated(extra), deposit, withdraw)
+}
+
+func withdrawProviderBalance(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	_, provider := parties(v)
+	v.CommitPreconditions()
+
+	amount := abi.NewTokenAmount(1_000_000)
+	deposit := v.Messages.Sugar().AddMarketBalance(provider.WorkerAddr.Robust, provider.MinerActorAddr.ID, amount, Nonce(0))
+	withdraw := v.Messages.Sugar().WithdrawMarketBalance(provider.WorkerAddr.Robust, provider.MinerActorAddr.ID, amount, Nonce(1))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.MarketEscrowEq(provider.MinerActorAddr.ID, big.Zero())
+
+	// the funds are sent to the owner, not to the worker that withdrew them.
+	v.Assert.BalanceEq(provider.OwnerAddr.ID, big.Add(balance, amount))
+	v.Assert.MessageSendersSatisfy(BalanceUpdated(big.Zero()), deposit, withdraw)
+}
+
+func activeDealCron(v *TipsetVectorBuilder) {
+	v.SetInitialEpochOffset(1)
+	base := v.ProtocolVersion.FirstEpoch + v.InitialEpochOffset
+
+	// the proving period of the provider starts at the epoch of the
+	// preconditions, so that no deadline holding sectors ends within the
+	// vector.
+	client := v.Actors.Account(address.SECP256K1, balance)
+	provider := v.Actors.Miner(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: base,
+		OwnerBalance:   balance,
+	})
+
+	// the deal starts 10 epochs after the preconditions, and is activated in
+	// a sector proven at the epoch of the preconditions.
+	proposal := dealProposalAt(base+10, client, provider, 0)
+	id := v.Actors.ActiveDeal(proposal, base)
+	v.Actors.MinerSectors(provider, MinerSectorsCfg{
+		Active: []SectorCfg{{
+			Number:     1,
+			Epoch:      base,
+			Expiration: proposal.EndEpoch,
+			DealIDs:    []abi.DealID{id},
+			DealWeight: big.Mul(big.NewIntUnsigned(uint64(proposal.PieceSize)), big.NewInt(int64(proposal.EndEpoch-base))),
+		}},
+		Deadline: 10,
+	})
+	v.CommitPreconditions()
+
+	// the deal is published and active, but has not been processed yet.
+	v.Assert.DealProposalEq(id, proposal)
+	v.Assert.DealStateEq(id, market.DealState{SectorStartEpoch: base, LastUpdatedEpoch: -1, SlashEpoch: -1})
+
+	// a tipset at the start epoch of the deal, after null rounds.
+	v.Tipsets.NullRounds(uint64(proposal.StartEpoch - base - 1))
+	ts := v.Tipsets.Next(abi.NewTokenAmount(100))
+	ts.Block(provider, 1)
+
+	v.CommitApplies()
+
+	v.Assert.EqualValues(proposal.StartEpoch-base, ts.EpochOffset)
+
+	// the deal is processed by the market cron tick at its start epoch, and
+	// not before.
+	lastNull := ts.NullRounds[len(ts.NullRounds)-1]
+	v.Assert.AtState(lastNull.PostStateRoot).DealStateEq(id, market.DealState{SectorStartEpoch: base, LastUpdatedEpoch: -1, SlashEpoch: -1})
+	v.Assert.DealStateEq(id, market.DealState{SectorStartEpoch: base, LastUpdatedEpoch: proposal.StartEpoch, SlashEpoch: -1})
+
+	// no storage fee is due yet: the balances of both parties are still
+	// locked in full.
+	v.Assert.MarketEscrowEq(client.ID, proposal.ClientBalanceRequirement())
+	v.Assert.MarketLockedEq(client.ID, proposal.ClientBalanceRequirement())
+	v.Assert.MarketEscrowEq(provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement())
+	v.Assert.MarketLockedEq(provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement())
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+const (
+	gasLimit  = 1_000_000_000
+	gasFeeCap = 200
+)
+
+var (
+	balance = big.Mul(big.NewInt(1_000), builtin.TokenPrecision)
+
+	// deal terms; the duration and collateral are within the bounds of the
+	// storage market policy.
+	dealDuration       = 200 * builtin.EpochsInDay
+	dealPrice          = abi.NewTokenAmount(1_000)
+	providerCollateral = builtin.TokenPrecision
+	clientCollateral   = abi.NewTokenAmount(1_000_000_000_000_000)
+)
+
+// versions are the protocol versions market vectors are generated against:
+// one per actors version.
+var versions = KnownProtocolVersionsOf("genesis", "actorsv2")
+
+func main() {
+	g := NewGenerator()
+	defer g.Close()
+
+	g.Group("publish",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-publish-deal",
+				Version: "v1",
+				Desc:    "a deal signed by the client is published by the provider worker; the balances of both parties are locked",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       publishDealOk,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-invalid-client-signature",
+				Version: "v1",
+				Desc:    "a deal whose proposal was not signed by the client is rejected",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       publishDealInvalidSignature,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-insufficient-client-funds",
+				Version: "v1",
+				Desc:    "a deal whose client has not escrowed enough funds to cover the storage fee and collateral is rejected",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       publishDealInsufficientFunds(false),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-insufficient-provider-funds",
+				Version: "v1",
+				Desc:    "a deal whose provider has not escrowed enough funds to cover the collateral is rejected",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       publishDealInsufficientFunds(true),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-not-provider-worker",
+				Version: "v1",
+				Desc:    "a deal published by an account other than the provider worker is rejected",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       publishDealNotWorker,
+		},
+	)
+
+	g.Group("activate",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-caller-not-miner",
+				Version: "v1",
+				Desc:    "published deals can only be activated by the provider miner actor; activations by accounts are rejected",
+				Comment: "successful activations require sealing proofs through the miner actor",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       activateDealNotMiner,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-active-deal-processed-at-start",
+				Version: "v1",
+				Desc:    "a published deal, activated in a sector of its provider, is first processed by the market cron tick at its start epoch; no storage fee is due yet",
+				Comment: "the deal and its sector are seeded in the preconditions, as activating them through messages requires sealing proofs",
+			},
+			SupportedVersions: versions,
+			TipsetFunc:        activeDealCron,
+		},
+	)
+
+	g.Group("withdraw",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-withdraw-unlocked-balance",
+				Version: "v1",
+				Desc:    "a client withdrawing its whole escrow only receives the funds not locked in published deals",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       withdrawUnlockedBalance,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-provider-withdraw-to-owner",
+				Version: "v1",
+				Desc:    "provider escrow withdrawn by the worker is sent to the owner of the miner",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       withdrawProviderBalance,
+		},
+	)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+const (
+	gasLimit  = 1_000_000_000
+	gasFeeCap = 200
+)
+
+var balance = big.Mul(big.NewInt(1_000), builtin.TokenPrecision)
+
+// versions are the protocol versions miner vectors are generated against:
+// one per actors version.
+var versions = KnownProtocolVersionsOf("genesis", "actorsv2")
+
+func main() {
+	g := NewGenerator()
+	defer g.Close()
+
+	g.Group("sectors",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-declare-faults",
+				Version: "v1",
+				Desc:    "the worker declares an active sector faulty, ahead of the fault cutoff of its deadline; the sector is still live, but faulty",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       declareFaults,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-terminate-sectors",
+				Version: "v1",
+				Desc:    "the worker terminates an active sector; the sector is no longer live, and the termination is processed right away",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       terminateSectors,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-extend-sector-expiration",
+				Version: "v1",
+				Desc:    "the worker extends the expiration of an active sector; the other sectors of the partition are unaffected",
+			},
+			SupportedVersions: versions,
+			MessageFunc:       extendSectorExpiration,
+		},
+	)
+
+	g.Group("post",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-chain-commit-declared-randomness",
+				Version: "v1",
+				Desc:    "the worker submits two Window PoSts, committing to the declared chain randomness and to the default one; only the former gets past the chain commitment check, failing on the missing partition",
+			},
+			SupportedVersions: postVersions,
+			MessageFunc:       postChainCommitRule,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-chain-commit-recorded-randomness",
+				Version: "v1",
+				Desc:    "the worker submits a Window PoSt committing to the default chain randomness, which is recorded into the vector; it gets past the chain commitment check, failing on the missing partition",
+			},
+			SupportedVersions: postVersions,
+			MessageFunc:       postChainCommitRecorded,
+		},
+	)
+}
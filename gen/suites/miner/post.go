@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-bitfield"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/crypto"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+	miner2 "github.com/chenjianmei111/specs-actors/v2/actors/builtin/miner"
+	proof2 "github.com/chenjianmei111/specs-actors/v2/actors/runtime/proof"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+	"github.com/chenjianmei111/test-vectors/schema"
+)
+
+// postVersions are the protocol versions Window PoSt vectors are generated
+// against. Actors v0 take no chain commitment.
+var postVersions = KnownProtocolVersionsOf("actorsv2")
+
+// commitRand is the chain randomness declared for the chain commit epoch.
+var commitRand = []byte("i_am_the_chain_commit_randomness")
+
+// posting sets up a miner whose worker is a funded account, at the start of
+// its proving period, with no sectors. It returns the miner, and the epoch
+// Window PoSts commit to the chain at: the epoch before the vector epoch,
+// which is within the challenge window of the first deadline.
+func posting(v *MessageVectorBuilder) (Miner, abi.ChainEpoch) {
+	base := v.ProtocolVersion.FirstEpoch
+
+	worker := v.Actors.Account(address.SECP256K1, balance)
+	m := v.Actors.Miner(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: base,
+		OwnerBalance:   balance,
+		Worker:         &worker,
+	})
+	return m, base - 1
+}
+
+// submitPoSt submits a Window PoSt for the first partition of the first
+// deadline, committing to the supplied chain randomness. The miner has no
+// partitions, so a PoSt that gets past the verification of the chain
+// commitment fails with ErrNotFound.
+func submitPoSt(v *MessageVectorBuilder, m Miner, commitEpoch abi.ChainEpoch, rand abi.Randomness, nonce uint64) *ApplicableMessage {
+	proofType, err := TestSealProofType.RegisteredWindowPoStProof()
+	v.Assert.NoError(err)
+
+	params := &miner2.SubmitWindowedPoStParams{
+		Deadline:         0,
+		Partitions:       []miner2.PoStPartition{{Index: 0, Skipped: bitfield.New()}},
+		Proofs:           []proof2.PoStProof{{PoStProof: proofType, ProofBytes: []byte("proof")}},
+		ChainCommitEpoch: commitEpoch,
+		ChainCommitRand:  rand,
+	}
+	return v.Messages.Typed(m.WorkerAddr.Robust, m.MinerActorAddr.Robust, MinerSubmitWindowedPoSt(params), Nonce(nonce))
+}
+
+func postChainCommitRule(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	m, commit := posting(v)
+	v.CommitPreconditions()
+
+	v.Randomness.Chain(crypto.DomainSeparationTag_PoStChainCommit, commit, nil, commitRand)
+
+	declared := submitPoSt(v, m, commit, commitRand, 0)
+	fallback := submitPoSt(v, m, commit, []byte(schema.DefaultRandomness), 1)
+	v.CommitApplies()
+
+	// the declared randomness is served, so only the PoSt committing to it
+	// gets past the verification of the chain commitment.
+	v.Assert.ExitCodeEq(declared.Result.ExitCode, exitcode.ErrNotFound)
+	v.Assert.ExitCodeEq(fallback.Result.ExitCode, exitcode.ErrIllegalArgument)
+}
+
+func postChainCommitRecorded(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	m, commit := posting(v)
+	v.CommitPreconditions()
+
+	v.Randomness.Record()
+
+	post := submitPoSt(v, m, commit, []byte(schema.DefaultRandomness), 0)
+	v.CommitApplies()
+
+	// no rule was declared, so the default randomness is served.
+	v.Assert.ExitCodeEq(post.Result.ExitCode, exitcode.ErrNotFound)
+
+	// the request for the chain commitment is recorded into the vector, along
+	// with the randomness served.
+	ret, ok := v.Randomness.Encode().Match(schema.RandomnessRule{
+		Kind:                schema.RandomnessChain,
+		DomainSeparationTag: int64(crypto.DomainSeparationTag_PoStChainCommit),
+		Epoch:               int64(commit),
+	})
+	v.Assert.True(ok, "chain commit randomness request not recorded")
+	v.Assert.Equal([]byte(schema.DefaultRandomness), ret)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-bitfield"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+	"github.com/chenjianmei111/specs-actors/actors/builtin/miner"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+// deadline is the deadline the sectors are assigned to. It is far enough from
+// the start of the proving period, which is the vector epoch, for its fault
+// cutoff not to have passed, and for it not to be challenged yet.
+const deadline = 10
+
+// expiration is the number of epochs after the vector epoch that sectors
+// expire at.
+const expiration = 200 * builtin.EpochsInDay
+
+// seeded sets up a miner whose worker is a funded account, with two active
+// sectors, numbered 1 and 2, in the first partition of the deadline. It
+// returns the miner, and the epoch the sectors expire at.
+func seeded(v *MessageVectorBuilder) (Miner, abi.ChainEpoch) {
+	base := v.ProtocolVersion.FirstEpoch
+
+	worker := v.Actors.Account(address.SECP256K1, balance)
+	m := v.Actors.Miner(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: base,
+		OwnerBalance:   balance,
+		Worker:         &worker,
+	})
+	v.Actors.MinerSectors(m, MinerSectorsCfg{
+		Active: []SectorCfg{
+			{Number: 1, Epoch: base, Expiration: base + expiration},
+			{Number: 2, Epoch: base, Expiration: base + expiration},
+		},
+		Deadline: deadline,
+	})
+	v.CommitPreconditions()
+
+	v.Assert.MinerPartitionSectorsEq(m.MinerActorAddr.ID, deadline, 0, []uint64{1, 2}, nil)
+	return m, base + expiration
+}
+
+func declareFaults(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	m, _ := seeded(v)
+
+	params := &miner.DeclareFaultsParams{Faults: []miner.FaultDeclaration{{
+		Deadline:  deadline,
+		Partition: 0,
+		Sectors:   bitfield.NewFromSet([]uint64{2}),
+	}}}
+	v.Messages.Typed(m.WorkerAddr.Robust, m.MinerActorAddr.Robust, MinerDeclareFaults(params), Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.MinerPartitionSectorsEq(m.MinerActorAddr.ID, deadline, 0, []uint64{1, 2}, []uint64{2})
+}
+
+func terminateSectors(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	m, _ := seeded(v)
+
+	params := &miner.TerminateSectorsParams{Terminations: []miner.TerminationDeclaration{{
+		Deadline:  deadline,
+		Partition: 0,
+		Sectors:   bitfield.NewFromSet([]uint64{1}),
+	}}}
+	terminate := v.Messages.Typed(m.WorkerAddr.Robust, m.MinerActorAddr.Robust, MinerTerminateSectors(params), Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+
+	// a single termination is processed within the message.
+	var ret miner.TerminateSectorsReturn
+	MustDeserialize(terminate.Result.Return, &ret)
+	v.Assert.True(ret.Done, "termination of sector 1 not processed")
+	v.Assert.MinerPartitionSectorsEq(m.MinerActorAddr.ID, deadline, 0, []uint64{2}, nil)
+}
+
+func extendSectorExpiration(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	m, expiry := seeded(v)
+
+	extended := expiry + 100*builtin.EpochsInDay
+	params := &miner.ExtendSectorExpirationParams{Extensions: []miner.ExpirationExtension{{
+		Deadline:      deadline,
+		Partition:     0,
+		Sectors:       bitfield.NewFromSet([]uint64{2}),
+		NewExpiration: extended,
+	}}}
+	v.Messages.Typed(m.WorkerAddr.Robust, m.MinerActorAddr.Robust, MinerExtendSectorExpiration(params), Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.MinerSectorExpirationEq(m.MinerActorAddr.ID, 1, expiry)
+	v.Assert.MinerSectorExpirationEq(m.MinerActorAddr.ID, 2, extended)
+	v.Assert.MinerPartitionSectorsEq(m.MinerActorAddr.ID, deadline, 0, []uint64{1, 2}, nil)
+}
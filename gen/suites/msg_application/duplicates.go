@@ -10,6 +10,9 @@ import (
 )
 
 func minerIncludesDuplicateMessages(v *TipsetVectorBuilder) {
+	// keep the bls address of the published vectors.
+	v.Wallet.UseLegacyBLSKeys()
+
 	var (
 		alice = v.Actors.Account(address.SECP256K1, balance1T)
 		bob   = v.Actors.Account(address.BLS, balance1T)
@@ -113,5 +113,44 @@ block validation we need to ensure behaviour is consistent across VM implementat
 		},
 	)
 
+	g.Group("signatures",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "msg-ok-signed-secp-bls",
+				Version: "v1",
+				Desc:    "signed messages from secp256k1 and BLS senders, addressed by ID and key address, are verified and applied",
+			},
+			Selector:    map[string]string{"signed_messages": "true"},
+			MessageFunc: okSignedSecpBLS,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "msg-apply-fail-invalid-signatures",
+				Version: "v1",
+				Desc:    "messages with corrupted secp256k1 and BLS signatures, or signed by a key other than the sender's, fail to apply and have no effect",
+			},
+			Selector:    map[string]string{"signed_messages": "true"},
+			MessageFunc: failInvalidSignatures,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "block-ok-signed-bls-before-secp",
+				Version: "v1",
+				Desc:    "the BLS messages of a signed block are applied before its secp256k1 messages, regardless of the order they were listed in",
+			},
+			Selector:   map[string]string{"signed_messages": "true"},
+			TipsetFunc: okSignedBlockBLSBeforeSecp,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "block-invalid-signatures-discarded",
+				Version: "v1",
+				Desc:    "blocks carrying a secp256k1 message with an invalid signature, or an invalid BLS aggregate, are discarded with all their messages",
+			},
+			Selector:   map[string]string{"signed_messages": "true"},
+			TipsetFunc: blocksWithInvalidSignaturesDiscarded,
+		},
+	)
+
 	g.Close()
 }
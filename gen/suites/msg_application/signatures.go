@@ -0,0 +1,128 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+// gasAllowance is the balance a sender needs to cover the maximum gas cost of
+// a message with the default gas parameters of these vectors.
+var gasAllowance = big.Mul(big.NewInt(1_000_000_000), big.NewInt(200))
+
+func okSignedSecpBLS(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(1_000_000_000), GasPremium(1), GasFeeCap(200), Signed())
+
+	alice := v.Actors.Account(address.SECP256K1, balance1T)
+	bob := v.Actors.Account(address.BLS, balance1T)
+	v.CommitPreconditions()
+
+	// secp256k1 senders are addressed by key address, so that the driver
+	// charges for their signature; BLS senders by ID address are resolved to
+	// their key address for signing.
+	secp := v.Messages.Sugar().Transfer(alice.Robust, bob.ID, Value(transferAmnt), Nonce(0))
+	bls := v.Messages.Sugar().Transfer(bob.ID, alice.ID, Value(transferAmnt), Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.Equal(exitcode.Ok, secp.Result.ExitCode)
+	v.Assert.Equal(exitcode.Ok, bls.Result.ExitCode)
+}
+
+func failInvalidSignatures(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(1_000_000_000), GasPremium(1), GasFeeCap(200), Signed())
+
+	alice := v.Actors.Account(address.SECP256K1, balance1T)
+	bob := v.Actors.Account(address.BLS, balance1T)
+	v.CommitPreconditions()
+
+	// corrupted signatures, and valid signatures by the wrong key.
+	corruptSecp := v.Messages.Sugar().Transfer(alice.Robust, bob.ID, Value(transferAmnt), Nonce(0), CorruptSignature())
+	corruptBLS := v.Messages.Sugar().Transfer(bob.ID, alice.ID, Value(transferAmnt), Nonce(0), CorruptSignature())
+	wrongSigner := v.Messages.Sugar().Transfer(alice.Robust, bob.ID, Value(transferAmnt), Nonce(0), SignedBy(bob.Robust))
+
+	// rejected messages have no effect: the nonces are still unused.
+	secp := v.Messages.Sugar().Transfer(alice.Robust, bob.ID, Value(transferAmnt), Nonce(0))
+	bls := v.Messages.Sugar().Transfer(bob.ID, alice.ID, Value(transferAmnt), Nonce(0))
+	v.CommitApplies()
+
+	for _, am := range []*ApplicableMessage{corruptSecp, corruptBLS, wrongSigner} {
+		v.Assert.True(am.Failed, "message %s with an invalid signature was applied", am.Message.Cid())
+	}
+	v.Assert.Equal(exitcode.Ok, secp.Result.ExitCode)
+	v.Assert.Equal(exitcode.Ok, bls.Result.ExitCode)
+}
+
+func okSignedBlockBLSBeforeSecp(v *TipsetVectorBuilder) {
+	// alice can only cover the gas of her message; the value she sends comes
+	// from bob's.
+	alice := v.Actors.Account(address.SECP256K1, gasAllowance)
+	bob := v.Actors.Account(address.BLS, balance1T)
+
+	miner := v.Actors.Miner(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: 0,
+		OwnerBalance:   big.Zero(),
+	})
+
+	v.CommitPreconditions()
+
+	v.StagedMessages.SetDefaults(GasLimit(1_000_000_000), GasPremium(1), GasFeeCap(200), Signed())
+	secp := v.StagedMessages.Sugar().Transfer(alice.Robust, bob.ID, Value(transferAmnt), Nonce(0))
+	bls := v.StagedMessages.Sugar().Transfer(bob.ID, alice.ID, Value(transferAmnt), Nonce(0))
+
+	// BLS messages are applied before secp256k1 messages, regardless of the
+	// order they are listed in.
+	ts := v.Tipsets.Next(abi.NewTokenAmount(100))
+	ts.Block(miner, 1, secp, bls)
+
+	v.CommitApplies()
+
+	v.Assert.Equal([]*ApplicableMessage{bls, secp}, v.Tipsets.Messages())
+	v.Assert.Equal(exitcode.Ok, bls.Result.ExitCode)
+	v.Assert.Equal(exitcode.Ok, secp.Result.ExitCode)
+}
+
+func blocksWithInvalidSignaturesDiscarded(v *TipsetVectorBuilder) {
+	var (
+		alice = v.Actors.Account(address.SECP256K1, balance1T)
+		bob   = v.Actors.Account(address.BLS, balance1T)
+		carol = v.Actors.Account(address.SECP256K1, balance1T)
+		dave  = v.Actors.Account(address.BLS, balance1T)
+	)
+
+	var minerA, minerB, minerC Miner
+	v.Actors.MinerN(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: 0,
+		OwnerBalance:   big.Zero(),
+	}, &minerA, &minerB, &minerC)
+
+	v.CommitPreconditions()
+
+	v.StagedMessages.SetDefaults(GasLimit(1_000_000_000), GasPremium(1), GasFeeCap(200), Signed())
+	var (
+		corruptSecp = v.StagedMessages.Sugar().Transfer(alice.Robust, carol.ID, Value(transferAmnt), Nonce(0), CorruptSignature())
+		validBLS    = v.StagedMessages.Sugar().Transfer(bob.ID, carol.ID, Value(transferAmnt), Nonce(0))
+		corruptBLS  = v.StagedMessages.Sugar().Transfer(dave.ID, carol.ID, Value(transferAmnt), Nonce(0), CorruptSignature())
+		validSecp   = v.StagedMessages.Sugar().Transfer(carol.Robust, alice.ID, Value(transferAmnt), Nonce(0))
+	)
+
+	ts := v.Tipsets.Next(abi.NewTokenAmount(100))
+	// a block with an invalid secp256k1 signature is discarded, along with its
+	// valid BLS messages.
+	ts.Block(minerA, 1, validBLS, corruptSecp)
+	// so is a block whose BLS aggregate is invalid.
+	ts.Block(minerB, 1, corruptBLS, validSecp)
+	// valid blocks are applied.
+	ts.Block(minerC, 1, validSecp)
+
+	v.CommitApplies()
+
+	for _, am := range []*ApplicableMessage{corruptSecp, validBLS, corruptBLS} {
+		v.Assert.Nil(am.Result, "message %s in a discarded block was applied", am.Message.Cid())
+	}
+	v.Assert.Equal(exitcode.Ok, validSecp.Result.ExitCode)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+
+	multisig0 "github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+func approvalRace(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	// alice, bob and charlie are signers, with two approvals required; dave
+	// is the recipient.
+	var alice, bob, charlie, dave AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob, &charlie, &dave)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID, charlie.ID}, 2, unlockDuration, amount, Nonce(0))
+
+	hash := proposeOk(v, proposeOpts{
+		multisigAddr: multisigAddr,
+		sender:       alice.ID,
+		recipient:    dave.ID,
+		amount:       amount,
+	}, Nonce(1))
+
+	// bob and charlie both approve; bob's approval lands first and executes
+	// the transaction, so charlie's finds no pending transaction.
+	bobApprove := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, 0, hash, Nonce(0), EpochOffset(unlockDuration+1))
+	v.Messages.ApplyOne(bobApprove)
+	v.Assert.Equal(exitcode.Ok, bobApprove.Result.ExitCode)
+
+	charlieApprove := v.Messages.Sugar().ApproveMultisig(charlie.ID, multisigAddr, 0, hash, Nonce(0), EpochOffset(unlockDuration+1))
+	v.Messages.ApplyOne(charlieApprove)
+	v.Assert.Equal(exitcode.ErrNotFound, charlieApprove.Result.ExitCode)
+
+	// alice can no longer cancel it either.
+	aliceCancel := v.Messages.Sugar().CancelMultisig(alice.ID, multisigAddr, 0, hash, Nonce(2), EpochOffset(unlockDuration+1))
+	v.Messages.ApplyOne(aliceCancel)
+	v.Assert.Equal(exitcode.ErrNotFound, aliceCancel.Result.ExitCode)
+
+	v.CommitApplies()
+
+	v.Assert.Equal(multisig0.ApproveReturn{Applied: true, Code: exitcode.Ok}, MultisigApproveReturn(bobApprove))
+
+	// the amount was transferred exactly once.
+	v.Assert.BalanceEq(multisigAddr, big.Zero())
+	v.Assert.BalanceEq(dave.ID, big.Add(initial, amount))
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
+}
+
+func approveHashMismatch(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob, charlie AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob, &charlie)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, unlockDuration, amount, Nonce(0))
+
+	hash := proposeOk(v, proposeOpts{
+		multisigAddr: multisigAddr,
+		sender:       alice.ID,
+		recipient:    charlie.ID,
+		amount:       amount,
+	}, Nonce(1))
+
+	// bob approves a transaction with the same ID, but sending a different
+	// amount; the approval is rejected, and the transaction stays pending.
+	tampered := *hash
+	tampered.Value = big.Add(amount, big.NewInt(1))
+	approve := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, 0, &tampered, Nonce(0), EpochOffset(unlockDuration+1))
+	v.Messages.ApplyOne(approve)
+	v.Assert.Equal(exitcode.ErrIllegalArgument, approve.Result.ExitCode)
+
+	v.CommitApplies()
+
+	v.Assert.BalanceEq(multisigAddr, amount)
+	v.Assert.BalanceEq(charlie.ID, initial)
+}
@@ -47,4 +47,86 @@ func main() {
 			MessageFunc: addSigner,
 		},
 	)
+
+	g.Group("signers",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-add-signer-explicit-approval",
+				Version: "v1",
+				Desc:    "the addition of a signer to a multisig requiring two approvals is pending until approved by the second signer",
+			},
+			MessageFunc: addSignerExplicitApproval,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-remove-signer-decrease-threshold",
+				Version: "v1",
+				Desc:    "an approved removal of a signer removes it and decreases the threshold",
+			},
+			MessageFunc: removeSigner,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-remove-signer-below-threshold",
+				Version: "v1",
+				Desc:    "an approved removal of a signer that would leave fewer signers than the threshold is executed but fails",
+			},
+			MessageFunc: removeSignerBelowThreshold,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-swap-signer",
+				Version: "v1",
+				Desc:    "a swapped out signer can no longer propose transactions",
+			},
+			MessageFunc: swapSigner,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-change-threshold",
+				Version: "v1",
+				Desc:    "a raised threshold applies to subsequent proposals; thresholds above the number of signers are rejected",
+			},
+			MessageFunc: changeThreshold,
+		},
+	)
+
+	g.Group("approvals",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-approval-race",
+				Version: "v1",
+				Desc:    "of two signers racing to approve a transaction, only the first one executes it; later approvals and cancellations find no transaction",
+			},
+			MessageFunc: approvalRace,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-approve-hash-mismatch",
+				Version: "v1",
+				Desc:    "an approval whose proposal hash does not match the pending transaction is rejected",
+			},
+			MessageFunc: approveHashMismatch,
+		},
+	)
+
+	g.Group("vesting",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-vesting-unlock",
+				Version: "v1",
+				Desc:    "funds locked by the vesting schedule of a multisig can only be sent once unlocked",
+			},
+			MessageFunc: vestingUnlock,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-lock-balance",
+				Version: "v1",
+				Desc:    "a multisig without vesting schedule locks its balance through an approved LockBalance transaction; the schedule can't be modified afterwards",
+			},
+			SupportedVersions: KnownProtocolVersionsFrom("actorsv2"),
+			MessageFunc:       lockBalance,
+		},
+	)
 }
@@ -7,7 +7,6 @@ import (
 
 	"github.com/chenjianmei111/lotus/chain/actors"
 	"github.com/chenjianmei111/lotus/chain/actors/builtin/multisig"
-	"github.com/chenjianmei111/lotus/chain/types"
 
 	"github.com/chenjianmei111/specs-actors/actors/builtin"
 	init0 "github.com/chenjianmei111/specs-actors/actors/builtin/init"
@@ -18,6 +17,8 @@ import (
 	. "github.com/chenjianmei111/test-vectors/gen/builders"
 )
 
+const unlockDuration = abi.ChainEpoch(10)
+
 func constructor(v *MessageVectorBuilder) {
 	var balance = abi.NewTokenAmount(1_000_000_000_000)
 	var amount = abi.NewTokenAmount(10)
@@ -28,15 +29,14 @@ func constructor(v *MessageVectorBuilder) {
 	alice := v.Actors.Account(address.SECP256K1, balance)
 	v.CommitPreconditions()
 
-	createMultisig(v, alice, []address.Address{alice.ID}, 1, Value(amount), Nonce(0))
+	createMultisig(v, alice, []address.Address{alice.ID}, 1, unlockDuration, amount, Nonce(0))
 	v.CommitApplies()
 }
 
 func proposeAndCancelOk(v *MessageVectorBuilder) {
 	var (
-		initial        = abi.NewTokenAmount(1_000_000_000_000)
-		amount         = abi.NewTokenAmount(10)
-		unlockDuration = abi.ChainEpoch(10)
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
 	)
 
 	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
@@ -47,7 +47,7 @@ func proposeAndCancelOk(v *MessageVectorBuilder) {
 	v.CommitPreconditions()
 
 	// create the multisig actor; created by alice.
-	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, Value(amount), Nonce(0))
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, unlockDuration, amount, Nonce(0))
 
 	// alice proposes that charlie should receive 'amount' FIL.
 	hash := proposeOk(v, proposeOpts{
@@ -58,17 +58,13 @@ func proposeAndCancelOk(v *MessageVectorBuilder) {
 	}, Nonce(1))
 
 	// bob cancels alice's transaction. This fails as bob did not create alice's transaction.
-	bobCancelMsg := v.Messages.Sugar().MultisigMessage(bob.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Cancel(multisigAddr, 0, hash)
-	}, Nonce(0))
+	bobCancelMsg := v.Messages.Sugar().CancelMultisig(bob.ID, multisigAddr, 0, hash, Nonce(0))
 	v.Messages.ApplyOne(bobCancelMsg)
 	v.Assert.Equal(bobCancelMsg.Result.ExitCode, exitcode.ErrForbidden)
 
 	// alice cancels their transaction; charlie doesn't receive any FIL,
 	// the multisig actor's balance is empty, and the transaction is canceled.
-	aliceCancelMsg := v.Messages.Sugar().MultisigMessage(alice.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Cancel(multisigAddr, 0, hash)
-	}, Nonce(2))
+	aliceCancelMsg := v.Messages.Sugar().CancelMultisig(alice.ID, multisigAddr, 0, hash, Nonce(2))
 	v.Messages.ApplyOne(aliceCancelMsg)
 	v.Assert.Equal(exitcode.Ok, aliceCancelMsg.Result.ExitCode)
 
@@ -77,38 +73,16 @@ func proposeAndCancelOk(v *MessageVectorBuilder) {
 	// verify balance is untouched.
 	v.Assert.BalanceEq(multisigAddr, amount)
 
-	// reload the multisig state and verify
-	multisigState, err := multisig.Load(v.StateTracker.Stores.ADTStore, v.StateTracker.Header(multisigAddr))
-	v.Assert.NoError(err)
-
-	threshold, _ := multisigState.Threshold()
-	v.Assert.Equal(uint64(2), threshold)
-
-	signers, _ := multisigState.Signers()
-	v.Assert.Equal([]address.Address{alice.ID, bob.ID}, signers)
-
-	initialBal, _ := multisigState.InitialBalance()
-	v.Assert.Equal(amount, initialBal)
-
-	startEpoch, _ := multisigState.StartEpoch()
-	v.Assert.Equal(v.ProtocolVersion.FirstEpoch, startEpoch)
-
-	unlockDur, _ := multisigState.UnlockDuration()
-	v.Assert.Equal(unlockDuration, unlockDur)
-
-	var pendingTxs int
-	_ = multisigState.ForEachPendingTxn(func(_ int64, _ multisig.Transaction) error {
-		pendingTxs++
-		return nil
-	})
-	v.Assert.Equal(0, pendingTxs)
+	// verify the multisig state.
+	v.Assert.MultisigSignersEq(multisigAddr, 2, alice.ID, bob.ID)
+	v.Assert.MultisigVestingEq(multisigAddr, vestingStart(v), unlockDuration, amount)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
 }
 
 func proposeAndApprove(v *MessageVectorBuilder) {
 	var (
-		initial        = abi.NewTokenAmount(1_000_000_000_000)
-		amount         = abi.NewTokenAmount(10)
-		unlockDuration = abi.ChainEpoch(10)
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
 	)
 
 	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
@@ -119,7 +93,7 @@ func proposeAndApprove(v *MessageVectorBuilder) {
 	v.CommitPreconditions()
 
 	// create the multisig actor; created by alice.
-	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, Value(amount), Nonce(0))
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, unlockDuration, amount, Nonce(0))
 
 	// alice proposes that charlie should receive 'amount' FIL.
 	hash := proposeOk(v, proposeOpts{
@@ -130,66 +104,37 @@ func proposeAndApprove(v *MessageVectorBuilder) {
 	}, Nonce(1))
 
 	// charlie proposes himself -> fails.
-	charliePropose := v.Messages.Sugar().MultisigMessage(charlie.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Propose(multisigAddr, charlie.ID, amount, builtin.MethodSend, nil)
-	}, Nonce(0))
+	charliePropose := v.Messages.Sugar().ProposeMultisig(charlie.ID, multisigAddr, charlie.ID, amount, builtin.MethodSend, nil, Nonce(0))
 	v.Messages.ApplyOne(charliePropose)
 	v.Assert.Equal(exitcode.ErrForbidden, charliePropose.Result.ExitCode)
 
 	// charlie attempts to accept the pending transaction -> fails.
-	charlieApprove := v.Messages.Sugar().MultisigMessage(charlie.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Approve(multisigAddr, 0, hash)
-	}, Nonce(1))
+	charlieApprove := v.Messages.Sugar().ApproveMultisig(charlie.ID, multisigAddr, 0, hash, Nonce(1))
 	v.Messages.ApplyOne(charlieApprove)
 	v.Assert.Equal(exitcode.ErrForbidden, charlieApprove.Result.ExitCode)
 
 	// bob approves transfer of 'amount' FIL to charlie.
 	// epoch is unlockDuration + 1
-	bobApprove := v.Messages.Sugar().MultisigMessage(bob.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Approve(multisigAddr, 0, hash)
-	}, Nonce(0), EpochOffset(unlockDuration+1))
+	bobApprove := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, 0, hash, Nonce(0), EpochOffset(unlockDuration+1))
 	v.Messages.ApplyOne(bobApprove)
 	v.Assert.Equal(exitcode.Ok, bobApprove.Result.ExitCode)
 
 	v.CommitApplies()
 
-	var approveRet multisig0.ApproveReturn
-	MustDeserialize(bobApprove.Result.Return, &approveRet)
 	v.Assert.Equal(multisig0.ApproveReturn{
 		Applied: true,
 		Code:    0,
 		Ret:     nil,
-	}, approveRet)
+	}, MultisigApproveReturn(bobApprove))
 
 	// assert that the multisig balance has been drained, and charlie's incremented.
 	v.Assert.BalanceEq(multisigAddr, big.Zero())
 	v.Assert.MessageSendersSatisfy(BalanceUpdated(amount), charliePropose, charlieApprove)
 
-	// reload the multisig state and verify
-	state, err := multisig.Load(v.StateTracker.Stores.ADTStore, v.StateTracker.Header(multisigAddr))
-	v.Assert.NoError(err)
-
-	threshold, _ := state.Threshold()
-	v.Assert.Equal(uint64(2), threshold)
-
-	signers, _ := state.Signers()
-	v.Assert.Equal([]address.Address{alice.ID, bob.ID}, signers)
-
-	initialBal, _ := state.InitialBalance()
-	v.Assert.Equal(amount, initialBal)
-
-	startEpoch, _ := state.StartEpoch()
-	v.Assert.Equal(v.ProtocolVersion.FirstEpoch, startEpoch)
-
-	unlockDur, _ := state.UnlockDuration()
-	v.Assert.Equal(unlockDuration, unlockDur)
-
-	var pendingTxs int
-	_ = state.ForEachPendingTxn(func(_ int64, _ multisig.Transaction) error {
-		pendingTxs++
-		return nil
-	})
-	v.Assert.Equal(0, pendingTxs)
+	// verify the multisig state.
+	v.Assert.MultisigSignersEq(multisigAddr, 2, alice.ID, bob.ID)
+	v.Assert.MultisigVestingEq(multisigAddr, vestingStart(v), unlockDuration, amount)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
 }
 
 func addSigner(v *MessageVectorBuilder) {
@@ -206,7 +151,7 @@ func addSigner(v *MessageVectorBuilder) {
 	v.CommitPreconditions()
 
 	// create the multisig actor; created by alice.
-	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID}, 1, Value(amount), Nonce(0))
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID}, 1, unlockDuration, amount, Nonce(0))
 
 	addParams := &multisig0.AddSignerParams{
 		Signer:   bob.ID,
@@ -219,39 +164,14 @@ func addSigner(v *MessageVectorBuilder) {
 
 	// go through the multisig wallet.
 	// since approvals = 1, this auto-approves the transaction.
-	v.Messages.Sugar().MultisigMessage(alice.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Propose(multisigAddr, multisigAddr, big.Zero(), builtin.MethodsMultisig.AddSigner, MustSerialize(addParams))
-	}, Nonce(2))
-
-	// TODO also exercise the approvals = 2 case with explicit approval.
+	v.Messages.Sugar().ProposeAddSigner(alice.ID, multisigAddr, bob.ID, false, Nonce(2))
 
 	v.CommitApplies()
 
-	// reload the multisig state and verify that bob is now a signer.
-	state, err := multisig.Load(v.StateTracker.Stores.ADTStore, v.StateTracker.Header(multisigAddr))
-	v.Assert.NoError(err)
-
-	threshold, _ := state.Threshold()
-	v.Assert.Equal(uint64(1), threshold)
-
-	signers, _ := state.Signers()
-	v.Assert.Equal([]address.Address{alice.ID, bob.ID}, signers)
-
-	initialBal, _ := state.InitialBalance()
-	v.Assert.Equal(amount, initialBal)
-
-	startEpoch, _ := state.StartEpoch()
-	v.Assert.Equal(v.ProtocolVersion.FirstEpoch, startEpoch)
-
-	unlockDur, _ := state.UnlockDuration()
-	v.Assert.Equal(abi.ChainEpoch(10), unlockDur)
-
-	var pendingTxs int
-	_ = state.ForEachPendingTxn(func(_ int64, _ multisig.Transaction) error {
-		pendingTxs++
-		return nil
-	})
-	v.Assert.Equal(0, pendingTxs)
+	// verify that bob is now a signer.
+	v.Assert.MultisigSignersEq(multisigAddr, 1, alice.ID, bob.ID)
+	v.Assert.MultisigVestingEq(multisigAddr, vestingStart(v), unlockDuration, amount)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
 }
 
 type proposeOpts struct {
@@ -262,30 +182,23 @@ type proposeOpts struct {
 }
 
 func proposeOk(v *MessageVectorBuilder, proposeOpts proposeOpts, opts ...MsgOpt) *multisig.ProposalHashData {
-	proposeMsg := v.Messages.Sugar().MultisigMessage(proposeOpts.sender, func(b multisig.MessageBuilder) (*types.Message, error) {
-		return b.Propose(proposeOpts.multisigAddr, proposeOpts.recipient, proposeOpts.amount, builtin.MethodSend, nil)
-	}, opts...)
+	proposeMsg := v.Messages.Sugar().ProposeMultisig(proposeOpts.sender, proposeOpts.multisigAddr, proposeOpts.recipient, proposeOpts.amount, builtin.MethodSend, nil, opts...)
 
 	v.Messages.ApplyOne(proposeMsg)
+	v.Assert.Equal(exitcode.Ok, proposeMsg.Result.ExitCode)
 
 	// verify that the multisig state contains the outstanding TX.
-	state, err := multisig.Load(v.StateTracker.Stores.ADTStore, v.StateTracker.Header(proposeOpts.multisigAddr))
-	v.Assert.NoError(err)
-
-	// load the transaction.
-	var tx *multisig.Transaction
-	_ = state.ForEachPendingTxn(func(id int64, txn multisig.Transaction) error {
-		tx = &txn
-		return nil
+	ret := MultisigProposeReturn(proposeMsg)
+	v.Assert.False(ret.Applied)
+	v.Assert.MultisigPendingTxnsEq(proposeOpts.multisigAddr, map[int64]multisig.Transaction{
+		int64(ret.TxnID): {
+			To:       proposeOpts.recipient,
+			Value:    proposeOpts.amount,
+			Method:   builtin.MethodSend,
+			Approved: []address.Address{proposeOpts.sender},
+		},
 	})
 
-	v.Assert.Equal(&multisig.Transaction{
-		To:       proposeOpts.recipient,
-		Value:    proposeOpts.amount,
-		Method:   builtin.MethodSend,
-		Approved: []address.Address{proposeOpts.sender},
-	}, tx)
-
 	return &multisig.ProposalHashData{
 		Requester: proposeOpts.sender,
 		To:        proposeOpts.recipient,
@@ -294,20 +207,20 @@ func proposeOk(v *MessageVectorBuilder, proposeOpts proposeOpts, opts ...MsgOpt)
 	}
 }
 
-func createMultisig(v *MessageVectorBuilder, creator AddressHandle, approvers []address.Address, threshold uint64, opts ...MsgOpt) address.Address {
-	const unlockDuration = abi.ChainEpoch(10)
+// vestingStart returns the vesting start of the multisig actors created by
+// createMultisig: the epoch of the vector. Actors v2 take it as a constructor
+// parameter, while actors v0 start vesting at the epoch of creation.
+func vestingStart(v *MessageVectorBuilder) abi.ChainEpoch {
+	return v.ProtocolVersion.FirstEpoch
+}
 
-	vestingStart := abi.ChainEpoch(0) // actors v1 only supports 0 for vesting start.
-	if v.ProtocolVersion.Actors >= actors.Version2 {
-		// use the protocol version's initial epoch.
-		vestingStart = v.ProtocolVersion.FirstEpoch
+func createMultisig(v *MessageVectorBuilder, creator AddressHandle, approvers []address.Address, threshold uint64, unlockDuration abi.ChainEpoch, amount abi.TokenAmount, opts ...MsgOpt) address.Address {
+	// create the multisig actor; actors v0 take no vesting start.
+	start := vestingStart(v)
+	if v.ProtocolVersion.Actors < actors.Version2 {
+		start = 0
 	}
-
-	// create the multisig actor.
-	msg := v.Messages.Sugar().MultisigMessage(creator.ID, func(b multisig.MessageBuilder) (*types.Message, error) {
-		// TODO this is ugly -- initial amount will be overriden by value MsgOpt.
-		return b.Create(approvers, threshold, vestingStart, unlockDuration, big.Zero())
-	}, opts...)
+	msg := v.Messages.Sugar().CreateMultisig(creator.ID, approvers, threshold, start, unlockDuration, amount, opts...)
 
 	v.Messages.ApplyOne(msg)
 
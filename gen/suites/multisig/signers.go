@@ -0,0 +1,210 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+
+	"github.com/chenjianmei111/lotus/chain/actors/builtin/multisig"
+
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+	multisig0 "github.com/chenjianmei111/specs-actors/actors/builtin/multisig"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+func addSignerExplicitApproval(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	// alice and bob are signers, with two approvals required; charlie is to
+	// be added.
+	var alice, bob, charlie AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob, &charlie)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, unlockDuration, amount, Nonce(0))
+
+	// alice proposes adding charlie; the transaction is pending bob's approval.
+	propose := v.Messages.Sugar().ProposeAddSigner(alice.ID, multisigAddr, charlie.ID, false, Nonce(1))
+	v.Messages.ApplyOne(propose)
+	v.Assert.Equal(exitcode.Ok, propose.Result.ExitCode)
+
+	ret := MultisigProposeReturn(propose)
+	v.Assert.False(ret.Applied)
+	v.Assert.MultisigSignersEq(multisigAddr, 2, alice.ID, bob.ID)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, map[int64]multisig.Transaction{
+		int64(ret.TxnID): {
+			To:       multisigAddr,
+			Value:    big.Zero(),
+			Method:   builtin.MethodsMultisig.AddSigner,
+			Params:   MustSerialize(&multisig0.AddSignerParams{Signer: charlie.ID}),
+			Approved: []address.Address{alice.ID},
+		},
+	})
+
+	// bob approves; charlie is added.
+	approve := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, uint64(ret.TxnID), nil, Nonce(0))
+	v.Messages.ApplyOne(approve)
+	v.Assert.Equal(exitcode.Ok, approve.Result.ExitCode)
+
+	v.CommitApplies()
+
+	v.Assert.Equal(multisig0.ApproveReturn{Applied: true, Code: exitcode.Ok}, MultisigApproveReturn(approve))
+	v.Assert.MultisigSignersEq(multisigAddr, 2, alice.ID, bob.ID, charlie.ID)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
+}
+
+func removeSigner(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob, charlie AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob, &charlie)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID, charlie.ID}, 2, unlockDuration, amount, Nonce(0))
+
+	// alice proposes removing charlie and decreasing the threshold; bob
+	// approves.
+	propose := v.Messages.Sugar().ProposeRemoveSigner(alice.ID, multisigAddr, charlie.ID, true, Nonce(1))
+	v.Messages.ApplyOne(propose)
+	v.Assert.Equal(exitcode.Ok, propose.Result.ExitCode)
+
+	approve := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, uint64(MultisigProposeReturn(propose).TxnID), nil, Nonce(0))
+	v.Messages.ApplyOne(approve)
+	v.Assert.Equal(exitcode.Ok, approve.Result.ExitCode)
+
+	v.CommitApplies()
+
+	v.Assert.Equal(multisig0.ApproveReturn{Applied: true, Code: exitcode.Ok}, MultisigApproveReturn(approve))
+	v.Assert.MultisigSignersEq(multisigAddr, 1, alice.ID, bob.ID)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
+}
+
+func removeSignerBelowThreshold(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 2, unlockDuration, amount, Nonce(0))
+
+	// alice proposes removing bob without decreasing the threshold, which
+	// would leave fewer signers than required approvals; bob approves, and
+	// the removal fails.
+	propose := v.Messages.Sugar().ProposeRemoveSigner(alice.ID, multisigAddr, bob.ID, false, Nonce(1))
+	v.Messages.ApplyOne(propose)
+	v.Assert.Equal(exitcode.Ok, propose.Result.ExitCode)
+
+	approve := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, uint64(MultisigProposeReturn(propose).TxnID), nil, Nonce(0))
+	v.Messages.ApplyOne(approve)
+	v.Assert.Equal(exitcode.Ok, approve.Result.ExitCode)
+
+	v.CommitApplies()
+
+	v.Assert.Equal(multisig0.ApproveReturn{Applied: true, Code: exitcode.ErrIllegalArgument}, MultisigApproveReturn(approve))
+	v.Assert.MultisigSignersEq(multisigAddr, 2, alice.ID, bob.ID)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
+}
+
+func swapSigner(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob, charlie AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob, &charlie)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 1, unlockDuration, amount, Nonce(0))
+
+	// alice replaces bob with charlie; approvals = 1, so it is applied right
+	// away.
+	propose := v.Messages.Sugar().ProposeSwapSigner(alice.ID, multisigAddr, bob.ID, charlie.ID, Nonce(1))
+	v.Messages.ApplyOne(propose)
+	v.Assert.Equal(exitcode.Ok, propose.Result.ExitCode)
+
+	// bob can no longer propose.
+	bobPropose := v.Messages.Sugar().ProposeMultisig(bob.ID, multisigAddr, bob.ID, amount, builtin.MethodSend, nil, Nonce(0))
+	v.Messages.ApplyOne(bobPropose)
+	v.Assert.Equal(exitcode.ErrForbidden, bobPropose.Result.ExitCode)
+
+	v.CommitApplies()
+
+	ret := MultisigProposeReturn(propose)
+	v.Assert.True(ret.Applied)
+	v.Assert.Equal(exitcode.Ok, ret.Code)
+	v.Assert.MultisigSignersEq(multisigAddr, 1, alice.ID, charlie.ID)
+	v.Assert.BalanceEq(multisigAddr, amount)
+}
+
+func changeThreshold(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob, charlie AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob, &charlie)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID, bob.ID}, 1, unlockDuration, amount, Nonce(0))
+
+	// alice raises the threshold to 2; approvals = 1, so it is applied right
+	// away.
+	raise := v.Messages.Sugar().ProposeChangeThreshold(alice.ID, multisigAddr, 2, Nonce(1))
+	v.Messages.ApplyOne(raise)
+	v.Assert.Equal(exitcode.Ok, raise.Result.ExitCode)
+	v.Assert.True(MultisigProposeReturn(raise).Applied)
+
+	// alice's next proposal is left pending bob's approval.
+	hash := proposeOk(v, proposeOpts{
+		multisigAddr: multisigAddr,
+		sender:       alice.ID,
+		recipient:    charlie.ID,
+		amount:       amount,
+	}, Nonce(2))
+
+	// a threshold above the number of signers is rejected.
+	tooHigh := v.Messages.Sugar().ProposeChangeThreshold(alice.ID, multisigAddr, 3, Nonce(3))
+	v.Messages.ApplyOne(tooHigh)
+	v.Assert.Equal(exitcode.Ok, tooHigh.Result.ExitCode)
+
+	approveTooHigh := v.Messages.Sugar().ApproveMultisig(bob.ID, multisigAddr, uint64(MultisigProposeReturn(tooHigh).TxnID), nil, Nonce(0))
+	v.Messages.ApplyOne(approveTooHigh)
+	v.Assert.Equal(exitcode.Ok, approveTooHigh.Result.ExitCode)
+
+	v.CommitApplies()
+
+	v.Assert.Equal(multisig0.ApproveReturn{Applied: true, Code: exitcode.ErrIllegalArgument}, MultisigApproveReturn(approveTooHigh))
+	v.Assert.MultisigSignersEq(multisigAddr, 2, alice.ID, bob.ID)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, map[int64]multisig.Transaction{
+		1: {
+			To:       hash.To,
+			Value:    hash.Value,
+			Method:   hash.Method,
+			Approved: []address.Address{alice.ID},
+		},
+	})
+}
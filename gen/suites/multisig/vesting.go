@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+func vestingUnlock(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(10)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob)
+	v.CommitPreconditions()
+
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID}, 1, unlockDuration, amount, Nonce(0))
+
+	// alice attempts to send the whole amount to bob before it has vested;
+	// the proposal is rejected.
+	early := v.Messages.Sugar().ProposeMultisig(alice.ID, multisigAddr, bob.ID, amount, builtin.MethodSend, nil, Nonce(1))
+	v.Messages.ApplyOne(early)
+	v.Assert.Equal(exitcode.ErrInsufficientFunds, early.Result.ExitCode)
+
+	// once the unlock duration has elapsed, the same proposal is executed.
+	vested := v.Messages.Sugar().ProposeMultisig(alice.ID, multisigAddr, bob.ID, amount, builtin.MethodSend, nil, Nonce(2), EpochOffset(unlockDuration+1))
+	v.Messages.ApplyOne(vested)
+	v.Assert.Equal(exitcode.Ok, vested.Result.ExitCode)
+
+	v.CommitApplies()
+
+	ret := MultisigProposeReturn(vested)
+	v.Assert.True(ret.Applied)
+	v.Assert.Equal(exitcode.Ok, ret.Code)
+
+	start := vestingStart(v)
+	v.Assert.MultisigVestingEq(multisigAddr, start, unlockDuration, amount)
+	v.Assert.MultisigLockedBalanceEq(multisigAddr, start, amount)
+	v.Assert.MultisigLockedBalanceEq(multisigAddr, start+unlockDuration, big.Zero())
+	v.Assert.BalanceEq(multisigAddr, big.Zero())
+	v.Assert.BalanceEq(bob.ID, big.Add(initial, amount))
+}
+
+func lockBalance(v *MessageVectorBuilder) {
+	var (
+		initial = abi.NewTokenAmount(1_000_000_000_000)
+		amount  = abi.NewTokenAmount(100)
+	)
+
+	v.Messages.SetDefaults(Value(big.Zero()), GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	var alice, bob AddressHandle
+	v.Actors.AccountN(address.SECP256K1, initial, &alice, &bob)
+	v.CommitPreconditions()
+
+	// a multisig without a vesting schedule.
+	multisigAddr := createMultisig(v, alice, []address.Address{alice.ID}, 1, 0, amount, Nonce(0))
+	v.Assert.MultisigLockedBalanceEq(multisigAddr, v.ProtocolVersion.FirstEpoch, big.Zero())
+
+	// alice locks the whole balance, vesting from the current epoch.
+	start := v.ProtocolVersion.FirstEpoch
+	lock := v.Messages.Sugar().ProposeLockBalance(alice.ID, multisigAddr, start, unlockDuration, amount, Nonce(1))
+	v.Messages.ApplyOne(lock)
+	v.Assert.Equal(exitcode.Ok, lock.Result.ExitCode)
+
+	ret := MultisigProposeReturn(lock)
+	v.Assert.True(ret.Applied)
+	v.Assert.Equal(exitcode.Ok, ret.Code)
+
+	// the locked balance can no longer be sent.
+	send := v.Messages.Sugar().ProposeMultisig(alice.ID, multisigAddr, bob.ID, amount, builtin.MethodSend, nil, Nonce(2))
+	v.Messages.ApplyOne(send)
+	v.Assert.Equal(exitcode.ErrInsufficientFunds, send.Result.ExitCode)
+
+	// the vesting schedule can't be modified once set; the transaction is
+	// executed, but fails.
+	relock := v.Messages.Sugar().ProposeLockBalance(alice.ID, multisigAddr, start, 2*unlockDuration, amount, Nonce(3))
+	v.Messages.ApplyOne(relock)
+	v.Assert.Equal(exitcode.Ok, relock.Result.ExitCode)
+
+	v.CommitApplies()
+
+	ret = MultisigProposeReturn(relock)
+	v.Assert.True(ret.Applied)
+	v.Assert.Equal(exitcode.ErrForbidden, ret.Code)
+
+	v.Assert.MultisigVestingEq(multisigAddr, start, unlockDuration, amount)
+	v.Assert.MultisigLockedBalanceEq(multisigAddr, start, amount)
+	v.Assert.MultisigLockedBalanceEq(multisigAddr, start+unlockDuration/2, big.Div(amount, big.NewInt(2)))
+	v.Assert.MultisigLockedBalanceEq(multisigAddr, start+unlockDuration, big.Zero())
+	v.Assert.BalanceEq(multisigAddr, amount)
+	v.Assert.MultisigPendingTxnsEq(multisigAddr, nil)
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/chenjianmei111/lotus/chain/actors/builtin/paych"
 	"github.com/chenjianmei111/lotus/chain/types"
 
-	init_ "github.com/chenjianmei111/specs-actors/actors/builtin/init"
 	paych0 "github.com/chenjianmei111/specs-actors/actors/builtin/paych"
 
 	. "github.com/chenjianmei111/test-vectors/gen/builders"
@@ -28,20 +27,14 @@ func happyPathCreate(v *MessageVectorBuilder) {
 	}, Value(toSend))
 	v.CommitApplies()
 
-	expectedActorAddr := AddressHandle{
-		ID:     MustNewIDAddr(MustIDFromAddress(receiver.ID) + 1),
-		Robust: sender.NextActorAddress(0, 0),
-	}
-
-	// Verify init actor return.
-	// TODO no abstraction for init messages.
-	var ret init_.ExecReturn
-	MustDeserialize(createMsg.Result.Return, &ret)
-	v.Assert.Equal(expectedActorAddr.Robust, ret.RobustAddress)
-	v.Assert.Equal(expectedActorAddr.ID, ret.IDAddress)
+	// Verify that the channel was created at the predicted addresses, and
+	// registered with the builder.
+	v.Assert.ExecReturnsPredicted(createMsg)
+	paychAddr := v.Actors.HandleFor(createMsg.PredictedActor.ID)
+	v.Assert.Equal(*createMsg.PredictedActor, paychAddr)
 
 	// Verify the paych state.
-	head := v.StateTracker.Header(ret.IDAddress)
+	head := v.StateTracker.Header(paychAddr.ID)
 	state, err := paych.Load(v.StateTracker.Stores.ADTStore, head)
 	v.Assert.NoError(err)
 
@@ -105,12 +98,12 @@ func happyPathUpdate(v *MessageVectorBuilder) {
 	// all messages succeeded.
 	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
 
-	// Verify init actor return.
-	var ret init_.ExecReturn
-	MustDeserialize(createMsg.Result.Return, &ret)
+	// Verify that the channel was created at the predicted addresses.
+	v.Assert.ExecReturnsPredicted(createMsg)
+	v.Assert.Equal(paychAddr, *createMsg.PredictedActor)
 
 	// Verify the paych state.
-	head := v.StateTracker.Header(ret.IDAddress)
+	head := v.StateTracker.Header(paychAddr.ID)
 	state, err := paych.Load(v.StateTracker.Stores.ADTStore, head)
 	v.Assert.NoError(err)
 
@@ -181,6 +174,7 @@ func happyPathCollect(v *MessageVectorBuilder) {
 
 	// all messages succeeded.
 	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
+	v.Assert.ExecReturnsPredicted(createMsg)
 
 	v.Assert.MessageSendersSatisfy(BalanceUpdated(big.Zero()), createMsg, updateMsg)
 	v.Assert.MessageSendersSatisfy(BalanceUpdated(toSend), settleMsg, collectMsg)
@@ -27,6 +27,14 @@ func main() {
 			},
 			TipsetFunc: minersAwardedNoPremiums,
 		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-miners-with-power-sharing-worker",
+				Version: "v1",
+				Desc:    "verifies that the power claimed by miners, two of which share a worker, is reported for the current epoch, and that every miner is awarded for its block",
+			},
+			TipsetFunc: minersWithPowerSharingWorker,
+		},
 	)
 
 	g.Group("penalties",
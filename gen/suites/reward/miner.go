@@ -1,15 +1,21 @@
 package main
 
 import (
+	"github.com/chenjianmei111/go-address"
 	"github.com/chenjianmei111/go-state-types/abi"
 	"github.com/chenjianmei111/go-state-types/big"
 	"github.com/chenjianmei111/go-state-types/exitcode"
 	"github.com/chenjianmei111/specs-actors/actors/builtin"
+	"github.com/chenjianmei111/specs-actors/actors/builtin/power"
+	"github.com/chenjianmei111/specs-actors/actors/util/smoothing"
 
 	. "github.com/chenjianmei111/test-vectors/gen/builders"
 )
 
 func minersAwardedNoPremiums(v *TipsetVectorBuilder) {
+	// keep the bls worker addresses of the published vectors.
+	v.Wallet.UseLegacyBLSKeys()
+
 	v.SetInitialEpochOffset(1)
 
 	var minerA, minerB, minerC Miner
@@ -49,6 +55,19 @@ func minersAwardedNoPremiums(v *TipsetVectorBuilder) {
 	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok))
 	v.Assert.EveryMessageSenderSatisfies(BalanceUpdated(big.Zero()))
 
+	// Verify that the rewards paid at epoch 11 follow the policy observed
+	// after the cron execution of the last null round (epoch 10).
+	v.Assert.Len(ts1.NullRounds, 10)
+	lastNull := ts1.NullRounds[len(ts1.NullRounds)-1]
+	v.Assert.EqualValues(10, lastNull.EpochOffset)
+	policy10 := v.Rewards.ForEpochOffset(lastNull.EpochOffset)
+	state10 := v.StateTracker.Fork(lastNull.PostStateRoot)
+	for _, m := range []Miner{minerA, minerB, minerC} {
+		addr := m.MinerActorAddr.ID
+		exp := big.Add(state10.Balance(addr), policy10.NextPerBlockReward)
+		v.Assert.AtState(ts1.PostStateRoot).BalanceEq(addr, exp)
+	}
+
 	// Verify that the reward actor balance has been updated between
 	// epoch 11 and epoch 12, based on the wincount=3.
 	policy := v.Rewards.ForEpochOffset(ts1.EpochOffset)
@@ -69,3 +88,66 @@ func minersAwardedNoPremiums(v *TipsetVectorBuilder) {
 	// Verify that the burnt gas has been sent to the burnt funds actor.
 	v.Assert.BalanceEq(builtin.BurntFundsActorAddr, big.Sum(CalculateBurntGas(transfer1), CalculateBurntGas(transfer2)))
 }
+
+func minersWithPowerSharingWorker(v *TipsetVectorBuilder) {
+	v.SetInitialEpochOffset(1)
+
+	// minerA and minerB share a worker, and claim power above the consensus
+	// minimum; minerC has its own worker, and no power.
+	var (
+		worker = v.Actors.Account(address.BLS, big.Zero())
+		rawA   = abi.NewStoragePower(100 << 40)
+		rawB   = abi.NewStoragePower(200 << 40)
+	)
+	cfg := MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: 1000,
+		OwnerBalance:   balance,
+		Worker:         &worker,
+	}
+	cfg.RawPower, cfg.QAPower = rawA, big.Mul(rawA, big.NewInt(2))
+	minerA := v.Actors.Miner(cfg)
+	cfg.RawPower, cfg.QAPower = rawB, rawB
+	minerB := v.Actors.Miner(cfg)
+	minerC := v.Actors.Miner(MinerActorCfg{
+		SealProofType:  TestSealProofType,
+		PeriodBoundary: 1000,
+		OwnerBalance:   balance,
+	})
+	v.CommitPreconditions()
+
+	// the miners sharing a worker have distinct addresses.
+	v.Assert.Equal(minerA.WorkerAddr, minerB.WorkerAddr)
+	v.Assert.NotEqual(minerA.MinerActorAddr, minerB.MinerActorAddr)
+
+	v.StagedMessages.SetDefaults(GasLimit(1_000_000_000), GasPremium(0), GasFeeCap(200))
+	query := v.StagedMessages.Typed(minerC.OwnerAddr.Robust, builtin.StoragePowerActorAddr, PowerCurrentTotalPower(nil), Nonce(0), Value(big.Zero()))
+
+	ts := v.Tipsets.Next(abi.NewTokenAmount(100))
+	ts.Block(minerA, 1, query)
+	ts.Block(minerB, 1)
+	ts.Block(minerC, 1)
+
+	v.CommitApplies()
+
+	// the power of the current epoch, queried before the cron execution of
+	// the tipset, accounts for the claims of the preconditions.
+	v.Assert.Equal(exitcode.Ok, query.Result.ExitCode)
+	var ret power.CurrentTotalPowerReturn
+	MustDeserialize(query.Result.Return, &ret)
+	totalQA := big.Add(big.Mul(rawA, big.NewInt(2)), rawB)
+	v.Assert.Equal(big.Add(rawA, rawB).String(), ret.RawBytePower.String())
+	v.Assert.Equal(totalQA.String(), ret.QualityAdjPower.String())
+	estimate := smoothing.NewEstimate(totalQA, big.Zero())
+	v.Assert.Equal(estimate.PositionEstimate.String(), ret.QualityAdjPowerSmoothed.PositionEstimate.String())
+	v.Assert.Equal(estimate.VelocityEstimate.String(), ret.QualityAdjPowerSmoothed.VelocityEstimate.String())
+
+	// every miner is awarded for its block, whatever its power, following the
+	// policy of the preconditions.
+	policy := v.Rewards.ForEpochOffset(0)
+	prev := v.StateTracker.Fork(v.PreRoot)
+	for _, m := range []Miner{minerA, minerB, minerC} {
+		addr := m.MinerActorAddr.ID
+		v.Assert.BalanceEq(addr, big.Add(prev.Balance(addr), policy.NextPerBlockReward))
+	}
+}
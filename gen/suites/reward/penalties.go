@@ -31,6 +31,9 @@ var basefee = abi.NewTokenAmount(100)
 // burnt funds actor.
 func minerPenalized(minerCnt int, messageFn func(v *TipsetVectorBuilder), checksFn func(v *TipsetVectorBuilder)) func(v *TipsetVectorBuilder) {
 	return func(v *TipsetVectorBuilder) {
+		// keep the bls worker addresses of the published vectors.
+		v.Wallet.UseLegacyBLSKeys()
+
 		v.SetInitialEpochOffset(1)
 
 		miners := make([]*Miner, minerCnt)
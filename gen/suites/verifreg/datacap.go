@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+	market0 "github.com/chenjianmei111/specs-actors/actors/builtin/market"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+func useBytesNotMarket(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	root := v.Actors.RootVerifier(balance)
+	var verifier, client AddressHandle
+	v.Actors.AccountN(address.SECP256K1, balance, &verifier, &client)
+	v.CommitPreconditions()
+
+	v.Messages.Sugar().AddVerifier(root.ID, verifier.ID, minDataCap, Nonce(0))
+	v.Messages.Sugar().AddVerifiedClient(verifier.ID, client.ID, minDataCap, Nonce(0))
+
+	// neither the root key, the verifier, nor the client can consume datacap.
+	byRoot := v.Messages.Sugar().UseBytes(root.ID, client.ID, minDataCap, Nonce(1))
+	byVerifier := v.Messages.Sugar().UseBytes(verifier.ID, client.ID, minDataCap, Nonce(1))
+	byClient := v.Messages.Sugar().UseBytes(client.ID, client.ID, minDataCap, Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok), byRoot, byVerifier, byClient)
+	for _, am := range []*ApplicableMessage{byRoot, byVerifier, byClient} {
+		v.Assert.ExitCodeEq(am.Result.ExitCode, exitcode.SysErrForbidden)
+	}
+	v.Assert.VerifiedClientDataCapEq(client.ID, minDataCap)
+}
+
+// verifiedDeal returns a builder of a vector where a client with the supplied
+// datacap publishes a verified deal of the supplied size, with the expected
+// exit code.
+func verifiedDeal(datacap abi.StoragePower, size abi.StoragePower, expected exitcode.ExitCode) func(v *MessageVectorBuilder) {
+	return func(v *MessageVectorBuilder) {
+		v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+		root := v.Actors.RootVerifier(balance)
+		var verifier, client, worker AddressHandle
+		v.Actors.AccountN(address.SECP256K1, balance, &verifier, &client)
+		v.Actors.AccountN(address.BLS, balance, &worker)
+		provider := v.Actors.Miner(MinerActorCfg{
+			SealProofType:  TestSealProofType,
+			PeriodBoundary: 0,
+			OwnerBalance:   balance,
+			Worker:         &worker,
+		})
+		v.CommitPreconditions()
+
+		v.Messages.Sugar().AddVerifier(root.ID, verifier.ID, datacap, Nonce(0))
+		v.Messages.Sugar().AddVerifiedClient(verifier.ID, client.ID, datacap, Nonce(0))
+
+		start := v.ProtocolVersion.FirstEpoch + 100
+		proposal := market0.DealProposal{
+			PieceCID:             MustNewPieceCID("verified-piece"),
+			PieceSize:            abi.PaddedPieceSize(size.Uint64()),
+			VerifiedDeal:         true,
+			Client:               client.ID,
+			Provider:             provider.MinerActorAddr.ID,
+			Label:                "verified-deal",
+			StartEpoch:           start,
+			EndEpoch:             start + dealDuration,
+			StoragePricePerEpoch: dealPrice,
+			ProviderCollateral:   providerCollateral,
+			ClientCollateral:     clientCollateral,
+		}
+		signed, err := v.Wallet.SignDealProposal(client.Robust, proposal)
+		v.Assert.NoError(err, "failed to sign deal proposal")
+
+		v.Messages.Sugar().AddMarketBalance(client.Robust, client.ID, proposal.ClientBalanceRequirement(), Nonce(0))
+		v.Messages.Sugar().AddMarketBalance(worker.Robust, provider.MinerActorAddr.ID, proposal.ProviderBalanceRequirement(), Nonce(0))
+		publish := v.Messages.Sugar().PublishStorageDeals(worker.Robust, []market0.ClientDealProposal{*signed}, Nonce(1))
+		v.CommitApplies()
+
+		v.Assert.EveryMessageResultSatisfies(ExitCode(exitcode.Ok), publish)
+		v.Assert.ExitCodeEq(publish.Result.ExitCode, expected)
+
+		switch remaining := big.Sub(datacap, size); {
+		case !expected.IsSuccess():
+			v.Assert.VerifiedClientDataCapEq(client.ID, datacap)
+			v.Assert.DealProposalMissing(0)
+		case remaining.LessThan(minDataCap):
+			// the client has too little datacap left to be a verified client.
+			v.Assert.VerifiedClientMissing(client.ID)
+			v.Assert.DealProposalEq(0, proposal)
+		default:
+			v.Assert.VerifiedClientDataCapEq(client.ID, remaining)
+			v.Assert.DealProposalEq(0, proposal)
+		}
+
+		// the verified registry holds no funds.
+		v.Assert.BalanceEq(builtin.VerifiedRegistryActorAddr, big.Zero())
+	}
+}
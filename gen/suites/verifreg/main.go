@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+	"github.com/chenjianmei111/specs-actors/actors/builtin"
+	verifreg0 "github.com/chenjianmei111/specs-actors/actors/builtin/verifreg"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+const (
+	gasLimit  = 1_000_000_000
+	gasFeeCap = 200
+)
+
+var (
+	balance = big.Mul(big.NewInt(1_000), builtin.TokenPrecision)
+
+	// minDataCap is the minimum allowance of verifiers and verified clients,
+	// and the minimum size of verified deals.
+	minDataCap = verifreg0.MinVerifiedDealSize
+
+	// deal terms; the duration and collateral are within the bounds of the
+	// storage market policy.
+	dealDuration       = 200 * builtin.EpochsInDay
+	dealPrice          = abi.NewTokenAmount(1_000)
+	providerCollateral = builtin.TokenPrecision
+	clientCollateral   = abi.NewTokenAmount(1_000_000_000_000_000)
+)
+
+func main() {
+	g := NewGenerator()
+	defer g.Close()
+
+	g.Group("verifiers",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-add-and-remove-verifier",
+				Version: "v1",
+				Desc:    "the root key adds a verifier with an allowance, and removes it",
+			},
+			MessageFunc: addAndRemoveVerifier,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-add-verifier-not-root",
+				Version: "v1",
+				Desc:    "only the root key can add verifiers",
+			},
+			MessageFunc: addVerifierNotRoot,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-remove-verifier-not-root",
+				Version: "v1",
+				Desc:    "only the root key can remove verifiers; not even verifiers can remove themselves",
+			},
+			MessageFunc: removeVerifierNotRoot,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-add-verifier-below-min-allowance",
+				Version: "v1",
+				Desc:    "verifiers cannot be added with an allowance below the minimum verified deal size",
+			},
+			MessageFunc: addVerifierBelowMin,
+		},
+	)
+
+	g.Group("clients",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-add-verified-client",
+				Version: "v1",
+				Desc:    "a verifier grants datacap to a client out of its allowance",
+			},
+			MessageFunc: addVerifiedClient(big.Mul(minDataCap, big.NewInt(2)), minDataCap, exitcode.Ok),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-add-verified-client-whole-allowance",
+				Version: "v1",
+				Desc:    "a verifier grants its whole allowance to a client, and remains a verifier with no allowance",
+			},
+			MessageFunc: addVerifiedClient(minDataCap, minDataCap, exitcode.Ok),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-add-verified-client-exceeds-allowance",
+				Version: "v1",
+				Desc:    "a verifier cannot grant more datacap than its allowance",
+			},
+			MessageFunc: addVerifiedClient(minDataCap, big.Add(minDataCap, big.NewInt(1)), exitcode.ErrIllegalArgument),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-add-verified-client-below-min-datacap",
+				Version: "v1",
+				Desc:    "a verifier cannot grant less datacap than the minimum verified deal size",
+			},
+			MessageFunc: addVerifiedClient(minDataCap, big.Sub(minDataCap, big.NewInt(1)), exitcode.ErrIllegalArgument),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-add-verified-client-not-verifier",
+				Version: "v1",
+				Desc:    "only verifiers can grant datacap to clients",
+			},
+			MessageFunc: addVerifiedClientNotVerifier,
+		},
+	)
+
+	g.Group("datacap",
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-use-bytes-not-market",
+				Version: "v1",
+				Desc:    "only the storage market actor can consume the datacap of verified clients",
+			},
+			MessageFunc: useBytesNotMarket,
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-verified-deal-uses-datacap",
+				Version: "v1",
+				Desc:    "publishing a verified deal consumes the datacap of the client by the piece size",
+			},
+			MessageFunc: verifiedDeal(big.Mul(minDataCap, big.NewInt(2)), minDataCap, exitcode.Ok),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "ok-verified-deal-exhausts-datacap",
+				Version: "v1",
+				Desc:    "a client whose datacap drops below the minimum verified deal size is no longer a verified client",
+			},
+			MessageFunc: verifiedDeal(minDataCap, minDataCap, exitcode.Ok),
+		},
+		&VectorDef{
+			Metadata: &Metadata{
+				ID:      "fail-verified-deal-exceeds-datacap",
+				Version: "v1",
+				Desc:    "a verified deal larger than the datacap of the client cannot be published",
+			},
+			MessageFunc: verifiedDeal(minDataCap, big.Mul(minDataCap, big.NewInt(2)), exitcode.ErrIllegalArgument),
+		},
+	)
+}
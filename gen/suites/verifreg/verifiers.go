@@ -0,0 +1,126 @@
+package main
+
+import (
+	"github.com/chenjianmei111/go-address"
+	"github.com/chenjianmei111/go-state-types/abi"
+	"github.com/chenjianmei111/go-state-types/big"
+	"github.com/chenjianmei111/go-state-types/exitcode"
+
+	. "github.com/chenjianmei111/test-vectors/gen/builders"
+)
+
+func addAndRemoveVerifier(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	root := v.Actors.RootVerifier(balance)
+	verifier := v.Actors.Account(address.SECP256K1, balance)
+	v.CommitPreconditions()
+
+	add := v.Messages.Sugar().AddVerifier(root.ID, verifier.ID, minDataCap, Nonce(0))
+	v.Messages.ApplyOne(add)
+	v.Assert.ExitCodeEq(add.Result.ExitCode, exitcode.Ok)
+	v.Assert.VerifierDataCapEq(verifier.ID, minDataCap)
+
+	remove := v.Messages.Sugar().RemoveVerifier(root.ID, verifier.ID, Nonce(1))
+	v.Messages.ApplyOne(remove)
+	v.Assert.ExitCodeEq(remove.Result.ExitCode, exitcode.Ok)
+	v.CommitApplies()
+
+	v.Assert.VerifierMissing(verifier.ID)
+	v.Assert.EveryMessageSenderSatisfies(BalanceUpdated(big.Zero()))
+	v.Assert.EveryMessageSenderSatisfies(NonceUpdated())
+}
+
+func addVerifierNotRoot(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	v.Actors.RootVerifier(balance)
+	var alice, verifier AddressHandle
+	v.Actors.AccountN(address.SECP256K1, balance, &alice, &verifier)
+	v.CommitPreconditions()
+
+	v.Messages.Sugar().AddVerifier(alice.ID, verifier.ID, minDataCap, Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.LastMessageResultSatisfies(ExitCode(exitcode.SysErrForbidden))
+	v.Assert.VerifierMissing(verifier.ID)
+}
+
+func removeVerifierNotRoot(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	root := v.Actors.RootVerifier(balance)
+	var alice, verifier AddressHandle
+	v.Actors.AccountN(address.SECP256K1, balance, &alice, &verifier)
+	v.CommitPreconditions()
+
+	add := v.Messages.Sugar().AddVerifier(root.ID, verifier.ID, minDataCap, Nonce(0))
+	byAlice := v.Messages.Sugar().RemoveVerifier(alice.ID, verifier.ID, Nonce(0))
+	byVerifier := v.Messages.Sugar().RemoveVerifier(verifier.ID, verifier.ID, Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.ExitCodeEq(add.Result.ExitCode, exitcode.Ok)
+	v.Assert.ExitCodeEq(byAlice.Result.ExitCode, exitcode.SysErrForbidden)
+	v.Assert.ExitCodeEq(byVerifier.Result.ExitCode, exitcode.SysErrForbidden)
+	v.Assert.VerifierDataCapEq(verifier.ID, minDataCap)
+}
+
+func addVerifierBelowMin(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	root := v.Actors.RootVerifier(balance)
+	verifier := v.Actors.Account(address.SECP256K1, balance)
+	v.CommitPreconditions()
+
+	v.Messages.Sugar().AddVerifier(root.ID, verifier.ID, big.Sub(minDataCap, big.NewInt(1)), Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.LastMessageResultSatisfies(ExitCode(exitcode.ErrIllegalArgument))
+	v.Assert.VerifierMissing(verifier.ID)
+}
+
+// addVerifiedClient returns a builder of a vector where a verifier with the
+// supplied allowance grants the supplied datacap to a client, with the
+// expected exit code.
+func addVerifiedClient(allowance, datacap abi.StoragePower, expected exitcode.ExitCode) func(v *MessageVectorBuilder) {
+	return func(v *MessageVectorBuilder) {
+		v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+		root := v.Actors.RootVerifier(balance)
+		var verifier, client AddressHandle
+		v.Actors.AccountN(address.SECP256K1, balance, &verifier, &client)
+		v.CommitPreconditions()
+
+		add := v.Messages.Sugar().AddVerifier(root.ID, verifier.ID, allowance, Nonce(0))
+		grant := v.Messages.Sugar().AddVerifiedClient(verifier.ID, client.ID, datacap, Nonce(0))
+		v.CommitApplies()
+
+		v.Assert.ExitCodeEq(add.Result.ExitCode, exitcode.Ok)
+		v.Assert.ExitCodeEq(grant.Result.ExitCode, expected)
+
+		if expected.IsSuccess() {
+			v.Assert.VerifierDataCapEq(verifier.ID, big.Sub(allowance, datacap))
+			v.Assert.VerifiedClientDataCapEq(client.ID, datacap)
+		} else {
+			v.Assert.VerifierDataCapEq(verifier.ID, allowance)
+			v.Assert.VerifiedClientMissing(client.ID)
+		}
+	}
+}
+
+func addVerifiedClientNotVerifier(v *MessageVectorBuilder) {
+	v.Messages.SetDefaults(GasLimit(gasLimit), GasPremium(1), GasFeeCap(gasFeeCap))
+
+	root := v.Actors.RootVerifier(balance)
+	client := v.Actors.Account(address.SECP256K1, balance)
+	v.CommitPreconditions()
+
+	// neither the root key, nor the client itself, are verifiers.
+	byRoot := v.Messages.Sugar().AddVerifiedClient(root.ID, client.ID, minDataCap, Nonce(0))
+	byClient := v.Messages.Sugar().AddVerifiedClient(client.ID, client.ID, minDataCap, Nonce(0))
+	v.CommitApplies()
+
+	v.Assert.ExitCodeEq(byRoot.Result.ExitCode, exitcode.ErrNotFound)
+	v.Assert.ExitCodeEq(byClient.Result.ExitCode, exitcode.ErrNotFound)
+	v.Assert.VerifiedClientMissing(client.ID)
+}
@@ -68,6 +68,8 @@ func actorResolutionSecpExistant(v *MessageVectorBuilder) {
 }
 
 func actorResolutionBlsExistant(v *MessageVectorBuilder) {
+	// keep the bls address of the published vectors.
+	v.Wallet.UseLegacyBLSKeys()
 	v.Messages.SetDefaults(GasLimit(1_000_000_000), GasPremium(1), GasFeeCap(200))
 
 	alice := v.Actors.Account(address.BLS, abi.NewTokenAmount(1_000_000_000_000))
@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/chenjianmei111/go-address v0.0.6
 	github.com/ipfs/go-cid v0.0.7
+	github.com/xeipuuv/gojsonschema v1.2.0
 )
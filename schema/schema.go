@@ -3,7 +3,6 @@ package schema
 import (
 	"encoding/base64"
 	"encoding/json"
-	"fmt"
 	"math/big"
 
 	"github.com/chenjianmei111/go-address"
@@ -45,6 +44,11 @@ const (
 	// the VM at address f098.
 	SelectorChaosActor = "chaos_actor"
 
+	// SelectorSignedMessages, if it appears and its value is literal "true",
+	// it indicates that the vector carries signed messages (see Message#Signed
+	// and Block#Signed), whose signatures the driver must verify.
+	SelectorSignedMessages = "signed_messages"
+
 	// SelectorMinProtocolVersion indicates the codename of the minimum protocol
 	// version that the VM must support in order to run this test vector. The
 	// value is a codename from a table kept outside the schema. Example good
@@ -66,6 +70,11 @@ type Metadata struct {
 	Comment string           `json:"comment,omitempty"`
 	Gen     []GenerationData `json:"gen"`
 	Tags    []string         `json:"tags,omitempty"`
+
+	// ContentHash is the hash of the vector, as returned by
+	// TestVector.ContentHash. It lives in the metadata so that it's excluded
+	// from the hashed content.
+	ContentHash string `json:"content_hash,omitempty"`
 }
 
 // GenerationData tags the source of this test case.
@@ -197,8 +206,9 @@ type TestVector struct {
 
 	Pre *Preconditions `json:"preconditions"`
 
-	ApplyMessages []Message `json:"apply_messages,omitempty"`
-	ApplyTipsets  []Tipset  `json:"apply_tipsets,omitempty"`
+	ApplyMessages []Message    `json:"apply_messages,omitempty"`
+	ApplyTipsets  []Tipset     `json:"apply_tipsets,omitempty"`
+	ApplyBlocks   []ApplyBlock `json:"apply_blocks,omitempty"`
 
 	Post        *Postconditions `json:"postconditions"`
 	Diagnostics *Diagnostics    `json:"diagnostics,omitempty"`
@@ -206,6 +216,12 @@ type TestVector struct {
 
 type Message struct {
 	Bytes Base64EncodedBytes `json:"bytes"`
+	// Signed indicates that Bytes is a serialized SignedMessage, rather than a
+	// bare Message. The driver must verify the signature against the sender's
+	// key before applying the message; a message with an invalid signature
+	// must not be applied, and is listed in
+	// Postconditions#ApplyMessageFailures.
+	Signed bool `json:"signed,omitempty"`
 	// EpochOffset represents the offset from the facet epoch where this message
 	// is applied. If missing, it must default to 0 (apply at the facet epoch).
 	// It.must be interpreted by the driver as an abi.ChainEpoch in Lotus, or
@@ -227,20 +243,35 @@ type Tipset struct {
 }
 
 type Block struct {
-	MinerAddr address.Address      `json:"miner_addr"`
-	WinCount  int64                `json:"win_count"`
-	Messages  []Base64EncodedBytes `json:"messages"`
-}
-
-// Validate validates this test vector against the JSON schema, and applies
-// further validation rules that cannot be enforced through JSON Schema.
-func (tv TestVector) Validate() error {
-	if tv.Class == ClassMessage {
-		if len(tv.Post.Receipts) != len(tv.ApplyMessages) {
-			return fmt.Errorf("length of postcondition receipts must match length of messages to apply")
-		}
-	}
-	return nil
+	MinerAddr address.Address `json:"miner_addr"`
+	WinCount  int64           `json:"win_count"`
+
+	// Messages are the serialized bare messages of an unsigned block, in
+	// application order. Signed blocks leave it empty; see Block#Signed.
+	Messages []Base64EncodedBytes `json:"messages,omitempty"`
+
+	// BLSMessages are the serialized bare messages of a signed block sent by
+	// BLS accounts. Their signatures are aggregated in BLSAggregate. They are
+	// applied before SECPMessages.
+	BLSMessages []Base64EncodedBytes `json:"bls_messages,omitempty"`
+	// SECPMessages are the serialized SignedMessages of a signed block sent
+	// by secp256k1 accounts.
+	SECPMessages []Base64EncodedBytes `json:"secp_messages,omitempty"`
+	// BLSAggregate is the aggregate signature of BLSMessages. Drivers must
+	// verify it, along with the signatures of SECPMessages, and discard the
+	// block from the tipset if any of them is invalid.
+	BLSAggregate *Signature `json:"bls_aggregate,omitempty"`
+}
+
+// Signed returns whether the block carries signed messages, split into
+// BLSMessages and SECPMessages, instead of bare Messages.
+func (b Block) Signed() bool {
+	return len(b.BLSMessages) > 0 || len(b.SECPMessages) > 0 || b.BLSAggregate != nil
+}
+
+// MessageCount returns the number of messages in the block, signed or not.
+func (b Block) MessageCount() int {
+	return len(b.Messages) + len(b.BLSMessages) + len(b.SECPMessages)
 }
 
 // MustMarshalJSON encodes the test vector to JSON and panics if it errors.
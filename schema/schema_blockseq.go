@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"math/big"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/ipfs/go-cid"
+)
+
+// BlockOutcome is the outcome a driver is expected to reach when validating
+// a block in a blockseq-class vector.
+type BlockOutcome string
+
+const (
+	// BlockAccept indicates that the block is valid, and must be accepted and
+	// executed by the driver.
+	BlockAccept = BlockOutcome("accept")
+	// BlockReject indicates that the block is invalid, and must be rejected by
+	// the driver. Rejected blocks do not affect the state.
+	BlockReject = BlockOutcome("reject")
+)
+
+// SignatureType is the type of a signature. Values match the SigType enum of
+// the Filecoin specification.
+type SignatureType byte
+
+const (
+	SigTypeSecp256k1 = SignatureType(1)
+	SigTypeBLS       = SignatureType(2)
+)
+
+// Signature is a typed signature.
+type Signature struct {
+	Type SignatureType      `json:"type"`
+	Data Base64EncodedBytes `json:"data"`
+}
+
+// Ticket is the ticket embedded in a block header.
+type Ticket struct {
+	VRFProof Base64EncodedBytes `json:"vrf_proof"`
+}
+
+// ElectionProof is the election proof embedded in a block header.
+type ElectionProof struct {
+	WinCount int64              `json:"win_count"`
+	VRFProof Base64EncodedBytes `json:"vrf_proof"`
+}
+
+// BeaconEntry is a drand beacon entry embedded in a block header.
+type BeaconEntry struct {
+	Round uint64             `json:"round"`
+	Data  Base64EncodedBytes `json:"data"`
+}
+
+// PoStProof is a winning PoSt proof embedded in a block header.
+type PoStProof struct {
+	// PoStProof must be interpreted by the driver as an
+	// abi.RegisteredPoStProof in Lotus, or equivalent type in other
+	// implementations.
+	PoStProof  int64              `json:"post_proof"`
+	ProofBytes Base64EncodedBytes `json:"proof_bytes"`
+}
+
+// BlockHeader is the decoded form of a block header. It exists to make
+// blockseq-class vectors legible; drivers must use the canonical encoding in
+// ApplyBlock.Bytes to compute block CIDs and to validate signatures.
+type BlockHeader struct {
+	Miner                 address.Address `json:"miner"`
+	Ticket                *Ticket         `json:"ticket,omitempty"`
+	ElectionProof         *ElectionProof  `json:"election_proof,omitempty"`
+	BeaconEntries         []BeaconEntry   `json:"beacon_entries,omitempty"`
+	WinPoStProof          []PoStProof     `json:"win_post_proof,omitempty"`
+	Parents               []cid.Cid       `json:"parents"`
+	ParentWeight          big.Int         `json:"parent_weight"`
+	Height                int64           `json:"height"`
+	ParentStateRoot       cid.Cid         `json:"parent_state_root"`
+	ParentMessageReceipts cid.Cid         `json:"parent_message_receipts"`
+	Messages              cid.Cid         `json:"messages"`
+	BLSAggregate          *Signature      `json:"bls_aggregate,omitempty"`
+	Timestamp             uint64          `json:"timestamp"`
+	BlockSig              *Signature      `json:"block_sig,omitempty"`
+	ForkSignaling         uint64          `json:"fork_signaling"`
+	ParentBaseFee         big.Int         `json:"parent_basefee"`
+}
+
+// ApplyBlock is a block that arrives at the driver in a blockseq-class
+// vector, along with the outcome the driver is expected to reach.
+//
+// Blocks are supplied in arrival order. Accepted blocks sharing the same
+// epoch offset form a tipset, which the driver must execute before moving on
+// to the next epoch.
+type ApplyBlock struct {
+	// EpochOffset represents the offset from the facet epoch at which this
+	// block arrives. It must be interpreted by the driver as an
+	// abi.ChainEpoch in Lotus, or equivalent type in other implementations.
+	EpochOffset int64 `json:"epoch_offset"`
+
+	// Bytes is the CBOR-encoded block header.
+	Bytes Base64EncodedBytes `json:"bytes"`
+
+	// Header is the decoded block header, for informational purposes.
+	Header BlockHeader `json:"header"`
+
+	// BLSMessages are the CBOR-encoded bare messages from BLS accounts
+	// included in this block, in order of inclusion. Their signatures are
+	// aggregated in Header.BLSAggregate.
+	BLSMessages []Base64EncodedBytes `json:"bls_messages,omitempty"`
+
+	// SECPMessages are the CBOR-encoded SignedMessages from secp256k1
+	// accounts included in this block, in order of inclusion. They are
+	// applied after BLSMessages.
+	SECPMessages []Base64EncodedBytes `json:"secp_messages,omitempty"`
+
+	// Expect is the outcome the driver must reach when validating this block.
+	Expect BlockOutcome `json:"expect"`
+
+	// Reason is an optional human-readable explanation of why the block is
+	// expected to be rejected.
+	Reason string `json:"reason,omitempty"`
+}
+
+// MessageCount returns the number of messages in the block.
+func (b ApplyBlock) MessageCount() int {
+	return len(b.BLSMessages) + len(b.SECPMessages)
+}
@@ -0,0 +1,267 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/ipfs/go-cid"
+)
+
+// This file contains minimal decoders for the binary payloads of a vector. They
+// check structure only, so that the schema package can validate vectors
+// without depending on an implementation.
+
+// carCIDs decompresses a gzipped CARv1 and returns the set of CIDs of the
+// blocks it contains.
+func carCIDs(car []byte) (map[cid.Cid]struct{}, error) {
+	if len(car) == 0 {
+		return nil, fmt.Errorf("empty CAR")
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(car))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress CAR: %w", err)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress CAR: %w", err)
+	}
+
+	// skip over the header; we don't care about the roots it declares.
+	hdrLen, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < hdrLen {
+		return nil, fmt.Errorf("malformed CAR header")
+	}
+	off := n + int(hdrLen)
+
+	cids := make(map[cid.Cid]struct{})
+	for off < len(data) {
+		l, n := binary.Uvarint(data[off:])
+		if n <= 0 || uint64(len(data)-off-n) < l {
+			return nil, fmt.Errorf("malformed CAR section at offset %d", off)
+		}
+		section := data[off+n : off+n+int(l)]
+		_, c, err := cid.CidFromBytes(section)
+		if err != nil {
+			return nil, fmt.Errorf("malformed CID in CAR section at offset %d: %w", off, err)
+		}
+		cids[c] = struct{}{}
+		off += n + int(l)
+	}
+	return cids, nil
+}
+
+// CBOR major types used by messages.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborArray  = 4
+)
+
+type cborReader struct {
+	buf []byte
+	off int
+}
+
+// header reads a CBOR item header, returning its major type and argument.
+func (r *cborReader) header() (byte, uint64, error) {
+	if r.off >= len(r.buf) {
+		return 0, 0, fmt.Errorf("unexpected end of input")
+	}
+	b := r.buf[r.off]
+	r.off++
+
+	maj, info := b>>5, b&0x1f
+	if info < 24 {
+		return maj, uint64(info), nil
+	}
+
+	var size int
+	switch info {
+	case 24:
+		size = 1
+	case 25:
+		size = 2
+	case 26:
+		size = 4
+	case 27:
+		size = 8
+	default:
+		return 0, 0, fmt.Errorf("unsupported additional info %d", info)
+	}
+	if len(r.buf)-r.off < size {
+		return 0, 0, fmt.Errorf("unexpected end of input")
+	}
+	var val uint64
+	for _, b := range r.buf[r.off : r.off+size] {
+		val = val<<8 | uint64(b)
+	}
+	r.off += size
+	return maj, val, nil
+}
+
+func (r *cborReader) uint(field string) (uint64, error) {
+	maj, val, err := r.header()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", field, err)
+	}
+	if maj != cborUint {
+		return 0, fmt.Errorf("%s: expected unsigned integer; got major type %d", field, maj)
+	}
+	return val, nil
+}
+
+func (r *cborReader) int(field string) error {
+	maj, _, err := r.header()
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	if maj != cborUint && maj != cborNegInt {
+		return fmt.Errorf("%s: expected integer; got major type %d", field, maj)
+	}
+	return nil
+}
+
+func (r *cborReader) bytes(field string) ([]byte, error) {
+	maj, l, err := r.header()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	if maj != cborBytes {
+		return nil, fmt.Errorf("%s: expected byte string; got major type %d", field, maj)
+	}
+	if uint64(len(r.buf)-r.off) < l {
+		return nil, fmt.Errorf("%s: unexpected end of input", field)
+	}
+	ret := r.buf[r.off : r.off+int(l)]
+	r.off += int(l)
+	return ret, nil
+}
+
+func (r *cborReader) address(field string) error {
+	b, err := r.bytes(field)
+	if err != nil {
+		return err
+	}
+	if _, err := address.NewFromBytes(b); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
+
+func (r *cborReader) bigint(field string) error {
+	b, err := r.bytes(field)
+	if err != nil {
+		return err
+	}
+	// big ints are encoded as a sign byte followed by the magnitude.
+	if len(b) > 0 && b[0] != 0 && b[0] != 1 {
+		return fmt.Errorf("%s: invalid sign byte %d", field, b[0])
+	}
+	return nil
+}
+
+// signature reads a signature, encoded as a byte string holding the signature
+// type followed by the signature data.
+func (r *cborReader) signature(field string) error {
+	b, err := r.bytes(field)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return fmt.Errorf("%s: empty signature", field)
+	}
+	if t := SignatureType(b[0]); t != SigTypeSecp256k1 && t != SigTypeBLS {
+		return fmt.Errorf("%s: unknown signature type %d", field, t)
+	}
+	return nil
+}
+
+// message reads a message, as produced by types.Message#Serialize in Lotus.
+func (r *cborReader) message() error {
+	maj, l, err := r.header()
+	if err != nil {
+		return err
+	}
+	if maj != cborArray || l != 10 {
+		return fmt.Errorf("expected array of 10 fields")
+	}
+
+	if version, err := r.uint("version"); err != nil {
+		return err
+	} else if version != 0 {
+		return fmt.Errorf("unsupported message version %d", version)
+	}
+	if err := r.address("to"); err != nil {
+		return err
+	}
+	if err := r.address("from"); err != nil {
+		return err
+	}
+	if _, err := r.uint("nonce"); err != nil {
+		return err
+	}
+	if err := r.bigint("value"); err != nil {
+		return err
+	}
+	if err := r.int("gas_limit"); err != nil {
+		return err
+	}
+	if err := r.bigint("gas_fee_cap"); err != nil {
+		return err
+	}
+	if err := r.bigint("gas_premium"); err != nil {
+		return err
+	}
+	if _, err := r.uint("method"); err != nil {
+		return err
+	}
+	if _, err := r.bytes("params"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// end checks that the whole input has been read.
+func (r *cborReader) end() error {
+	if r.off != len(r.buf) {
+		return fmt.Errorf("%d trailing bytes", len(r.buf)-r.off)
+	}
+	return nil
+}
+
+// decodeMessage checks that the supplied bytes decode as a CBOR-encoded
+// message, as produced by types.Message#Serialize in Lotus.
+func decodeMessage(b []byte) error {
+	r := &cborReader{buf: b}
+	if err := r.message(); err != nil {
+		return err
+	}
+	return r.end()
+}
+
+// decodeSignedMessage checks that the supplied bytes decode as a CBOR-encoded
+// signed message, as produced by types.SignedMessage#Serialize in Lotus.
+func decodeSignedMessage(b []byte) error {
+	r := &cborReader{buf: b}
+
+	maj, l, err := r.header()
+	if err != nil {
+		return err
+	}
+	if maj != cborArray || l != 2 {
+		return fmt.Errorf("expected array of 2 fields")
+	}
+	if err := r.message(); err != nil {
+		return fmt.Errorf("message: %w", err)
+	}
+	if err := r.signature("signature"); err != nil {
+		return err
+	}
+	return r.end()
+}
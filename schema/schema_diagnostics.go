@@ -0,0 +1,190 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/chenjianmei111/go-address"
+)
+
+// Known diagnostics formats.
+const (
+	// DiagnosticsLotusExecutionTraceV1 is a gzipped JSON array of Lotus
+	// ExecutionTraces. The gzipped data is base64-encoded before being set
+	// as the diagnostics data, so it ends up base64-encoded twice in the
+	// serialized vector.
+	DiagnosticsLotusExecutionTraceV1 = "Lotus-ExecutionTrace-V1"
+
+	// DiagnosticsExecutionTraceV2 is a gzipped JSON array of ExecutionTrace,
+	// set as the diagnostics data as-is. It is implementation-agnostic, and
+	// it supersedes DiagnosticsLotusExecutionTraceV1.
+	DiagnosticsExecutionTraceV2 = "ExecutionTrace-V2"
+)
+
+// ExecutionTrace is the call tree resulting from applying a message.
+type ExecutionTrace struct {
+	Message    *TraceMessage    `json:"msg"`
+	Receipt    *Receipt         `json:"receipt"`
+	Error      string           `json:"error,omitempty"`
+	GasCharges []GasCharge      `json:"gas_charges,omitempty"`
+	Subcalls   []ExecutionTrace `json:"subcalls,omitempty"`
+}
+
+// TraceMessage is a message sent during execution, either by an account
+// (top-level) or by an actor (subcalls).
+type TraceMessage struct {
+	From   address.Address    `json:"from"`
+	To     address.Address    `json:"to"`
+	Nonce  uint64             `json:"nonce"`
+	Value  *big.Int           `json:"value"`
+	Method uint64             `json:"method"`
+	Params Base64EncodedBytes `json:"params"`
+}
+
+// GasCharge is a gas charge incurred during execution.
+type GasCharge struct {
+	Name       string `json:"name"`
+	TotalGas   int64  `json:"total_gas"`
+	ComputeGas int64  `json:"compute_gas"`
+	StorageGas int64  `json:"storage_gas"`
+}
+
+// EncodeTraces encodes the supplied execution traces in the
+// DiagnosticsExecutionTraceV2 format.
+func EncodeTraces(traces []ExecutionTrace) (*Diagnostics, error) {
+	serialized, err := json.Marshal(traces)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(serialized); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &Diagnostics{Format: DiagnosticsExecutionTraceV2, Data: buf.Bytes()}, nil
+}
+
+// DecodeTraces decodes the execution traces carried by these diagnostics,
+// which must be in one of the known diagnostics formats.
+func (d *Diagnostics) DecodeTraces() ([]ExecutionTrace, error) {
+	switch d.Format {
+	case DiagnosticsLotusExecutionTraceV1:
+		raw, err := base64.StdEncoding.DecodeString(string(d.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode diagnostics: %w", err)
+		}
+		var traces []lotusTraceV1
+		if err := gunzipJSON(raw, &traces); err != nil {
+			return nil, err
+		}
+		ret := make([]ExecutionTrace, 0, len(traces))
+		for _, t := range traces {
+			conv, err := t.convert()
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, conv)
+		}
+		return ret, nil
+
+	case DiagnosticsExecutionTraceV2:
+		var traces []ExecutionTrace
+		err := gunzipJSON(d.Data, &traces)
+		return traces, err
+
+	default:
+		return nil, fmt.Errorf("unsupported diagnostics format: %s", d.Format)
+	}
+}
+
+func gunzipJSON(data []byte, out interface{}) error {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to inflate diagnostics: %w", err)
+	}
+	defer r.Close()
+
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to inflate diagnostics: %w", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to parse diagnostics: %w", err)
+	}
+	return nil
+}
+
+// lotusTraceV1 mirrors the JSON serialization of a Lotus ExecutionTrace.
+type lotusTraceV1 struct {
+	Msg *struct {
+		From   address.Address
+		To     address.Address
+		Nonce  uint64
+		Value  string
+		Method uint64
+		Params Base64EncodedBytes
+	}
+	MsgRct *struct {
+		ExitCode int64
+		Return   Base64EncodedBytes
+		GasUsed  int64
+	}
+	Error      string
+	GasCharges []struct {
+		Name       string
+		TotalGas   int64 `json:"tg"`
+		ComputeGas int64 `json:"cg"`
+		StorageGas int64 `json:"sg"`
+	}
+	Subcalls []lotusTraceV1
+}
+
+func (t lotusTraceV1) convert() (ExecutionTrace, error) {
+	ret := ExecutionTrace{Error: t.Error}
+	if m := t.Msg; m != nil {
+		value, ok := new(big.Int).SetString(m.Value, 10)
+		if !ok {
+			return ret, fmt.Errorf("invalid message value in trace: %q", m.Value)
+		}
+		ret.Message = &TraceMessage{
+			From:   m.From,
+			To:     m.To,
+			Nonce:  m.Nonce,
+			Value:  value,
+			Method: m.Method,
+			Params: m.Params,
+		}
+	}
+	if r := t.MsgRct; r != nil {
+		ret.Receipt = &Receipt{
+			ExitCode:    r.ExitCode,
+			ReturnValue: r.Return,
+			GasUsed:     r.GasUsed,
+		}
+	}
+	for _, gc := range t.GasCharges {
+		ret.GasCharges = append(ret.GasCharges, GasCharge{
+			Name:       gc.Name,
+			TotalGas:   gc.TotalGas,
+			ComputeGas: gc.ComputeGas,
+			StorageGas: gc.StorageGas,
+		})
+	}
+	for _, sub := range t.Subcalls {
+		conv, err := sub.convert()
+		if err != nil {
+			return ret, err
+		}
+		ret.Subcalls = append(ret.Subcalls, conv)
+	}
+	return ret, nil
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ContentHashPrefix prefixes content hashes, identifying the hash function.
+const ContentHashPrefix = "sha256:"
+
+// CanonicalJSON returns the canonical JSON encoding of the test vector. It
+// is compact, object properties follow the field order of the Go types (map
+// keys are sorted), and HTML characters are not escaped. Two vectors with the
+// same content always have the same canonical encoding.
+func (tv TestVector) CanonicalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(&tv); err != nil {
+		return nil, err
+	}
+	// Encode terminates the value with a newline; drop it.
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// ContentHash returns the hash of the canonical JSON encoding of the test
+// vector, without the _meta property, formatted as "sha256:<hex>". Vectors
+// that only differ in metadata have the same content hash.
+func (tv TestVector) ContentHash() (string, error) {
+	tv.Meta = nil
+	b, err := tv.CanonicalJSON()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return ContentHashPrefix + hex.EncodeToString(sum[:]), nil
+}
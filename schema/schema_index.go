@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IndexFile is the name of the corpus index file, located at the root of the
+// corpus. It is not a test vector, and must be skipped when walking the
+// corpus for vectors.
+const IndexFile = "index.json"
+
+// VectorFiles expands the supplied paths into the list of vector files they
+// contain. Directories are walked recursively, in lexical order, and only
+// .json files other than IndexFile are retained.
+func VectorFiles(paths ...string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(path, ".json") || info.Name() == IndexFile {
+				return nil
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("walking %s: %w", p, err)
+		}
+	}
+	return files, nil
+}
+
+// Index summarizes the vectors in a corpus, so that drivers can plan runs
+// and select subsets of vectors without parsing every vector.
+type Index struct {
+	Vectors []IndexEntry `json:"vectors"`
+}
+
+// IndexEntry summarizes a test vector.
+type IndexEntry struct {
+	// Path is the path of the vector file relative to the corpus root, using
+	// forward slashes.
+	Path     string    `json:"path"`
+	Class    Class     `json:"class"`
+	ID       string    `json:"id"`
+	Version  string    `json:"version,omitempty"`
+	Variants []Variant `json:"variants"`
+	Selector Selector  `json:"selector,omitempty"`
+	Hints    []string  `json:"hints,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+
+	// Messages is the total number of messages the vector applies, across
+	// all tipsets and blocks.
+	Messages int `json:"messages"`
+
+	// ContentHash is the content hash of the vector; see
+	// TestVector.ContentHash.
+	ContentHash string `json:"content_hash"`
+}
+
+// NewIndexEntry summarizes the supplied test vector, located at the supplied
+// path relative to the corpus root.
+func NewIndexEntry(path string, tv *TestVector) (IndexEntry, error) {
+	hash, err := tv.ContentHash()
+	if err != nil {
+		return IndexEntry{}, err
+	}
+
+	e := IndexEntry{
+		Path:        path,
+		Class:       tv.Class,
+		Selector:    tv.Selector,
+		Hints:       tv.Hints,
+		Messages:    len(tv.ApplyMessages),
+		ContentHash: hash,
+	}
+	if tv.Meta != nil {
+		e.ID, e.Version, e.Tags = tv.Meta.ID, tv.Meta.Version, tv.Meta.Tags
+	}
+	if tv.Pre != nil {
+		e.Variants = tv.Pre.Variants
+	}
+	for _, ts := range tv.ApplyTipsets {
+		for _, blk := range ts.Blocks {
+			e.Messages += blk.MessageCount()
+		}
+	}
+	for _, blk := range tv.ApplyBlocks {
+		e.Messages += blk.MessageCount()
+	}
+	return e, nil
+}
+
+// Query selects index entries. An entry matches if it satisfies every
+// criterion that's set; zero-valued criteria match all entries.
+type Query struct {
+	// Class is the class of the vector.
+	Class Class
+	// ID is a regex matched against the vector ID.
+	ID *regexp.Regexp
+	// Selector entries must all be present in the selector of the vector,
+	// with the same values.
+	Selector Selector
+	// Tags must all be present in the tags of the vector.
+	Tags []string
+	// Hints must all be present in the hints of the vector.
+	Hints []string
+	// ExcludeHints must all be absent from the hints of the vector.
+	ExcludeHints []string
+	// Variant is the ID of a variant the vector must have.
+	Variant string
+	// NetworkVersion, if not nil, is a network version at which at least one
+	// variant of the vector must run.
+	NetworkVersion *uint
+}
+
+// Query returns the entries of the index matching the supplied query, in
+// index order.
+func (idx Index) Query(q Query) []IndexEntry {
+	var ret []IndexEntry
+	for _, e := range idx.Vectors {
+		if e.Matches(q) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Matches returns whether the entry matches the supplied query.
+func (e IndexEntry) Matches(q Query) bool {
+	if q.Class != "" && e.Class != q.Class {
+		return false
+	}
+	if q.ID != nil && !q.ID.MatchString(e.ID) {
+		return false
+	}
+	for k, v := range q.Selector {
+		if actual, ok := e.Selector[k]; !ok || actual != v {
+			return false
+		}
+	}
+	for _, t := range q.Tags {
+		if !containsString(e.Tags, t) {
+			return false
+		}
+	}
+	for _, h := range q.Hints {
+		if !containsString(e.Hints, h) {
+			return false
+		}
+	}
+	for _, h := range q.ExcludeHints {
+		if containsString(e.Hints, h) {
+			return false
+		}
+	}
+	if q.Variant != "" || q.NetworkVersion != nil {
+		var found bool
+		for _, v := range e.Variants {
+			if (q.Variant == "" || v.ID == q.Variant) && (q.NetworkVersion == nil || v.NetworkVersion == *q.NetworkVersion) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(set []string, s string) bool {
+	for _, e := range set {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
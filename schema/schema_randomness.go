@@ -1,6 +1,9 @@
 package schema
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+)
 
 // RandomnessKind specifies the type of randomness that is being requested.
 type RandomnessKind string
@@ -10,6 +13,10 @@ const (
 	RandomnessChain  = RandomnessKind("chain")
 )
 
+// DefaultRandomness is the value drivers must return when a randomness
+// request matches no rule.
+const DefaultRandomness = "i_am_random_____i_am_random_____"
+
 // RandomnessRule represents a rule to evaluate randomness matches against.
 // This encodes to JSON as an array. See godocs on the Randomness type for
 // more info.
@@ -83,3 +90,18 @@ type RandomnessMatch struct {
 	On     RandomnessRule     `json:"on"`
 	Return Base64EncodedBytes `json:"ret"`
 }
+
+// Match returns the value of the first rule that matches the requested
+// randomness, or false if none does. A rule matches when its kind, domain
+// separation tag, epoch and entropy are equal to those requested.
+func (r Randomness) Match(requested RandomnessRule) ([]byte, bool) {
+	for _, m := range r {
+		if m.On.Kind == requested.Kind &&
+			m.On.DomainSeparationTag == requested.DomainSeparationTag &&
+			m.On.Epoch == requested.Epoch &&
+			bytes.Equal(m.On.Entropy, requested.Entropy) {
+			return m.Return, true
+		}
+	}
+	return nil, false
+}
@@ -1,9 +1,23 @@
 package schema
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/chenjianmei111/go-address"
+	"github.com/ipfs/go-cid"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 func TestRandomnessCircularSerde(t *testing.T) {
@@ -48,3 +62,566 @@ func TestRandomnessCircularSerde(t *testing.T) {
 	}
 
 }
+
+func TestRandomnessMatch(t *testing.T) {
+	rand := Randomness{
+		{
+			On:     RandomnessRule{Kind: RandomnessChain, DomainSeparationTag: 2, Epoch: 10, Entropy: []byte("foo")},
+			Return: []byte("first"),
+		},
+		{
+			On:     RandomnessRule{Kind: RandomnessChain, DomainSeparationTag: 2, Epoch: 10, Entropy: []byte("foo")},
+			Return: []byte("shadowed"),
+		},
+		{
+			On:     RandomnessRule{Kind: RandomnessBeacon, DomainSeparationTag: 2, Epoch: 10},
+			Return: []byte("beacon"),
+		},
+	}
+
+	ret, ok := rand.Match(RandomnessRule{Kind: RandomnessChain, DomainSeparationTag: 2, Epoch: 10, Entropy: []byte("foo")})
+	if !ok || string(ret) != "first" {
+		t.Fatalf("expected first rule to match; got: %s, %t", ret, ok)
+	}
+
+	ret, ok = rand.Match(RandomnessRule{Kind: RandomnessBeacon, DomainSeparationTag: 2, Epoch: 10})
+	if !ok || string(ret) != "beacon" {
+		t.Fatalf("expected beacon rule to match; got: %s, %t", ret, ok)
+	}
+
+	for _, rule := range []RandomnessRule{
+		{Kind: RandomnessChain, DomainSeparationTag: 3, Epoch: 10, Entropy: []byte("foo")},
+		{Kind: RandomnessChain, DomainSeparationTag: 2, Epoch: 11, Entropy: []byte("foo")},
+		{Kind: RandomnessChain, DomainSeparationTag: 2, Epoch: 10, Entropy: []byte("bar")},
+		{Kind: RandomnessBeacon, DomainSeparationTag: 2, Epoch: 10, Entropy: []byte("foo")},
+	} {
+		if ret, ok := rand.Match(rule); ok {
+			t.Fatalf("expected no match for rule %+v; got: %s", rule, ret)
+		}
+	}
+}
+
+// testCID returns a sha2-256 dag-cbor CID of the supplied data.
+func testCID(t *testing.T, data []byte) cid.Cid {
+	c, err := cid.Prefix{Version: 1, Codec: cid.DagCBOR, MhType: 0x12, MhLength: -1}.Sum(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// testCAR returns a gzipped CARv1 containing the supplied blocks, along with
+// their CIDs.
+func testCAR(t *testing.T, blocks ...[]byte) ([]byte, []cid.Cid) {
+	var (
+		buf  bytes.Buffer
+		cids []cid.Cid
+	)
+	writeSection := func(b []byte) {
+		var l [binary.MaxVarintLen64]byte
+		buf.Write(l[:binary.PutUvarint(l[:], uint64(len(b)))])
+		buf.Write(b)
+	}
+
+	// {"roots": [], "version": 1}
+	writeSection([]byte{0xa2, 0x65, 'r', 'o', 'o', 't', 's', 0x80, 0x67, 'v', 'e', 'r', 's', 'i', 'o', 'n', 0x01})
+	for _, b := range blocks {
+		c := testCID(t, b)
+		cids = append(cids, c)
+		writeSection(append(c.Bytes(), b...))
+	}
+
+	var out bytes.Buffer
+	w := gzip.NewWriter(&out)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes(), cids
+}
+
+func mustIDAddress(id uint64) address.Address {
+	addr, err := address.NewIDAddress(id)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+// testMessage returns a CBOR-encoded message from f0101 to f0100.
+func testMessage() []byte {
+	to, from := mustIDAddress(100).Bytes(), mustIDAddress(101).Bytes()
+
+	msg := []byte{0x8a, 0x00} // array(10), version 0
+	msg = append(msg, 0x40|byte(len(to)))
+	msg = append(msg, to...)
+	msg = append(msg, 0x40|byte(len(from)))
+	msg = append(msg, from...)
+	msg = append(msg, 0x00, 0x40, 0x18, 0x64) // nonce 0, value 0, gas limit 100
+	msg = append(msg, 0x40, 0x40, 0x00, 0x40) // fee cap 0, premium 0, method 0, no params
+	return msg
+}
+
+// testSignedMessage returns a CBOR-encoded signed message wrapping
+// testMessage, with a signature of the supplied type.
+func testSignedMessage(typ SignatureType) []byte {
+	msg := append([]byte{0x82}, testMessage()...)   // array(2), message
+	return append(msg, 0x43, byte(typ), 0xaa, 0xbb) // signature type, data
+}
+
+func testValidVector(t *testing.T) TestVector {
+	car, cids := testCAR(t, []byte{0x01}, []byte{0x02})
+	return TestVector{
+		Class: ClassMessage,
+		CAR:   car,
+		Pre: &Preconditions{
+			Variants:  []Variant{{ID: "genesis", Epoch: 0, NetworkVersion: 0}, {ID: "breeze", Epoch: 41280, NetworkVersion: 1}},
+			StateTree: &StateTree{RootCID: cids[0]},
+		},
+		ApplyMessages: []Message{{Bytes: testMessage()}, {Bytes: testMessage()}},
+		Post: &Postconditions{
+			ApplyMessageFailures: []int{1},
+			StateTree:            &StateTree{RootCID: cids[1]},
+			Receipts:             []*Receipt{{ExitCode: 0, GasUsed: 10}, nil},
+		},
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	tv := testValidVector(t)
+	if err := tv.Validate(); err != nil {
+		t.Fatalf("expected valid vector; got: %s", err)
+	}
+
+	tv.Class = ClassTipset
+	tv.ApplyMessages = nil
+	tv.Post.ApplyMessageFailures = nil
+	tv.ApplyTipsets = []Tipset{
+		{EpochOffset: 0, Blocks: []Block{{Messages: []Base64EncodedBytes{testMessage()}}}},
+		{EpochOffset: 2},
+	}
+	tv.Post.ReceiptsRoots = []cid.Cid{testCID(t, []byte{0x03}), testCID(t, []byte{0x04})}
+	if err := tv.Validate(); err != nil {
+		t.Fatalf("expected valid vector; got: %s", err)
+	}
+
+	// signed messages.
+	tv.ApplyTipsets[1].Blocks = []Block{{
+		BLSMessages:  []Base64EncodedBytes{testMessage()},
+		SECPMessages: []Base64EncodedBytes{testSignedMessage(SigTypeSecp256k1)},
+		BLSAggregate: &Signature{Type: SigTypeBLS, Data: []byte{0xaa}},
+	}}
+	if err := tv.Validate(); err != nil {
+		t.Fatalf("expected valid vector; got: %s", err)
+	}
+
+	tv = testValidVector(t)
+	tv.ApplyMessages[0] = Message{Bytes: testSignedMessage(SigTypeBLS), Signed: true}
+	if err := tv.Validate(); err != nil {
+		t.Fatalf("expected valid vector; got: %s", err)
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	expectFields := func(t *testing.T, tv TestVector, fields ...string) {
+		t.Helper()
+
+		err := tv.Validate()
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("expected ValidationErrors; got: %v", err)
+		}
+		var actual []string
+		for _, e := range errs {
+			actual = append(actual, e.Field)
+		}
+		if !reflect.DeepEqual(actual, fields) {
+			t.Fatalf("expected violations on fields %v; got: %s", fields, errs)
+		}
+	}
+
+	t.Run("message", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.Pre.Variants = append(tv.Pre.Variants, Variant{ID: "genesis"})
+		tv.Post.ApplyMessageFailures = []int{0, 3}
+		tv.Post.Receipts = append(tv.Post.Receipts, nil)
+		tv.ApplyMessages[1].Bytes = tv.ApplyMessages[1].Bytes[:10]
+
+		expectFields(t, tv,
+			"preconditions.variants[2].id",
+			"postconditions.receipts",
+			"postconditions.apply_message_failures[0]",
+			"postconditions.apply_message_failures[1]",
+			"postconditions.receipts[1]",
+			"postconditions.receipts[2]",
+			"apply_messages[1].bytes",
+		)
+	})
+
+	t.Run("signed message", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.ApplyMessages[0].Signed = true
+		tv.ApplyMessages[1] = Message{Bytes: testSignedMessage(3), Signed: true}
+
+		expectFields(t, tv,
+			"apply_messages[0].bytes",
+			"apply_messages[1].bytes",
+		)
+	})
+
+	t.Run("tipset", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.Class = ClassTipset
+		tv.Pre.Variants = nil
+		tv.ApplyMessages = nil
+		tv.ApplyTipsets = []Tipset{
+			{EpochOffset: 1},
+			{EpochOffset: 1, Blocks: []Block{{Messages: []Base64EncodedBytes{{0x80}}}}},
+		}
+		tv.Post.StateTree.RootCID = testCID(t, []byte{0x05})
+
+		expectFields(t, tv,
+			"preconditions.variants",
+			"postconditions.receipts_roots",
+			"apply_tipsets[1].epoch_offset",
+			"postconditions.state_tree.root_cid",
+			"apply_tipsets[1].blocks[0].messages[0]",
+		)
+	})
+
+	t.Run("signed block", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.Class = ClassTipset
+		tv.ApplyMessages = nil
+		tv.Post.ApplyMessageFailures = nil
+		tv.ApplyTipsets = []Tipset{{Blocks: []Block{{
+			Messages:     []Base64EncodedBytes{testMessage()},
+			BLSMessages:  []Base64EncodedBytes{testSignedMessage(SigTypeBLS)},
+			SECPMessages: []Base64EncodedBytes{testMessage()},
+		}}}}
+		tv.Post.ReceiptsRoots = []cid.Cid{testCID(t, []byte{0x03})}
+
+		expectFields(t, tv,
+			"apply_tipsets[0].blocks[0].messages",
+			"apply_tipsets[0].blocks[0].bls_messages[0]",
+			"apply_tipsets[0].blocks[0].secp_messages[0]",
+		)
+	})
+
+	t.Run("blockseq", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.Class = ClassBlockSeq
+		tv.ApplyMessages = nil
+		tv.Post.ApplyMessageFailures = nil
+		tv.ApplyBlocks = []ApplyBlock{
+			{EpochOffset: 1, BLSMessages: []Base64EncodedBytes{testMessage()}, SECPMessages: []Base64EncodedBytes{testSignedMessage(SigTypeSecp256k1)}},
+			{EpochOffset: 0, BLSMessages: []Base64EncodedBytes{testSignedMessage(SigTypeBLS)}, SECPMessages: []Base64EncodedBytes{testMessage()}},
+		}
+
+		expectFields(t, tv,
+			"apply_blocks[1].epoch_offset",
+			"apply_blocks[1].bls_messages[0]",
+			"apply_blocks[1].secp_messages[0]",
+		)
+	})
+
+	t.Run("car", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.CAR = []byte("not gzipped")
+
+		expectFields(t, tv, "car")
+	})
+
+	t.Run("content_hash", func(t *testing.T) {
+		tv := testValidVector(t)
+		tv.Meta = &Metadata{ID: "vector", ContentHash: ContentHashPrefix + "00"}
+
+		expectFields(t, tv, "_meta.content_hash")
+	})
+}
+
+func TestJSONSchema(t *testing.T) {
+	js, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader("file://../schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate := func(t *testing.T, tv TestVector) *gojsonschema.Result {
+		t.Helper()
+		res, err := js.Validate(gojsonschema.NewBytesLoader(tv.MustMarshalJSON()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	tv := testValidVector(t)
+	tv.Meta = &Metadata{ID: "vector", Gen: []GenerationData{{Source: "test"}}}
+	tv.Class = ClassTipset
+	tv.ApplyMessages = nil
+	tv.Post.ApplyMessageFailures = nil
+	tv.ApplyTipsets = []Tipset{
+		{EpochOffset: 0, Blocks: []Block{{MinerAddr: mustIDAddress(1000), Messages: []Base64EncodedBytes{testMessage()}}}},
+		{EpochOffset: 1, Blocks: []Block{{
+			MinerAddr:    mustIDAddress(1000),
+			BLSMessages:  []Base64EncodedBytes{testMessage()},
+			SECPMessages: []Base64EncodedBytes{testSignedMessage(SigTypeSecp256k1)},
+			BLSAggregate: &Signature{Type: SigTypeBLS, Data: []byte{0xaa}},
+		}}},
+	}
+	tv.Post.ReceiptsRoots = []cid.Cid{testCID(t, []byte{0x03}), testCID(t, []byte{0x04})}
+	if res := validate(t, tv); !res.Valid() {
+		t.Fatalf("expected signed tipset vector to be valid; got: %v", res.Errors())
+	}
+
+	// blocks can't carry both bare and signed messages.
+	tv.ApplyTipsets[1].Blocks[0].Messages = []Base64EncodedBytes{testMessage()}
+	if res := validate(t, tv); res.Valid() {
+		t.Fatal("expected block with bare and signed messages to be invalid")
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	tv := testValidVector(t)
+	tv.Meta = &Metadata{ID: "vector", Desc: "a <b> & c"}
+
+	hash, err := tv.ContentHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, ContentHashPrefix) || len(hash) != len(ContentHashPrefix)+64 {
+		t.Fatalf("malformed content hash: %s", hash)
+	}
+
+	// the hash does not depend on the metadata.
+	tv.Meta = &Metadata{ID: "other", ContentHash: hash}
+	if other, err := tv.ContentHash(); err != nil || other != hash {
+		t.Fatalf("expected hash to ignore metadata; got: %s, %v", other, err)
+	}
+	if err := tv.Validate(); err != nil {
+		t.Fatalf("expected valid vector; got: %s", err)
+	}
+
+	// but it does depend on everything else.
+	tv.Pre.Variants = tv.Pre.Variants[:1]
+	if other, _ := tv.ContentHash(); other == hash {
+		t.Fatal("expected hash to change with content")
+	}
+
+	// the canonical encoding round-trips, and does not escape HTML.
+	b, err := tv.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tv.Meta.ID = "a <b>"; !bytes.Contains(tv.MustMarshalJSON(), []byte(`\u003c`)) {
+		t.Fatal("expected the standard encoding to escape HTML")
+	}
+	if b, _ := tv.CanonicalJSON(); !bytes.Contains(b, []byte(`"a <b>"`)) {
+		t.Fatalf("expected the canonical encoding not to escape HTML; got: %s", b)
+	}
+	var decoded TestVector
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := decoded.CanonicalJSON(); !bytes.Equal(again, b) {
+		t.Fatalf("canonical encoding did not round-trip:\n%s\n%s", b, again)
+	}
+}
+
+// lotusTraces is a set of traces as serialized by Lotus.
+const lotusTraces = `[{
+	"Msg": {"Version": 0, "To": "f0100", "From": "f0101", "Nonce": 1, "Value": "100", "GasLimit": 1000, "GasFeeCap": "1", "GasPremium": "1", "Method": 2, "Params": "AQI="},
+	"MsgRct": {"ExitCode": 0, "Return": "Aw==", "GasUsed": 500},
+	"Error": "",
+	"Duration": 1234,
+	"GasCharges": [{"Name": "OnChainMessage", "loc": null, "tg": 300, "cg": 100, "sg": 200, "tt": 10}],
+	"Subcalls": [{
+		"Msg": {"Version": 0, "To": "f04", "From": "f0100", "Nonce": 0, "Value": "0", "Method": 3, "Params": null},
+		"MsgRct": {"ExitCode": 16, "Return": null, "GasUsed": 0},
+		"Error": "forbidden",
+		"GasCharges": null,
+		"Subcalls": null
+	}]
+}]`
+
+func TestDecodeTracesLotusV1(t *testing.T) {
+	var buf bytes.Buffer
+	b64 := base64.NewEncoder(base64.StdEncoding, &buf)
+	gz := gzip.NewWriter(b64)
+	if _, err := gz.Write([]byte(lotusTraces)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b64.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// round-trip through JSON, as the diagnostics would be read from a vector.
+	serialized, err := json.Marshal(&Diagnostics{Format: DiagnosticsLotusExecutionTraceV1, Data: buf.Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Diagnostics
+	if err := json.Unmarshal(serialized, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err := d.DecodeTraces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 1 {
+		t.Fatalf("expected 1 trace; got: %d", len(traces))
+	}
+
+	root := traces[0]
+	if m := root.Message; m.From != mustIDAddress(101) || m.To != mustIDAddress(100) || m.Value.String() != "100" || m.Method != 2 || !bytes.Equal(m.Params, []byte{1, 2}) {
+		t.Fatalf("unexpected message: %+v", m)
+	}
+	if r := root.Receipt; r.ExitCode != 0 || r.GasUsed != 500 || !bytes.Equal(r.ReturnValue, []byte{3}) {
+		t.Fatalf("unexpected receipt: %+v", r)
+	}
+	if expected := []GasCharge{{Name: "OnChainMessage", TotalGas: 300, ComputeGas: 100, StorageGas: 200}}; !reflect.DeepEqual(root.GasCharges, expected) {
+		t.Fatalf("unexpected gas charges: %+v", root.GasCharges)
+	}
+	if len(root.Subcalls) != 1 {
+		t.Fatalf("expected 1 subcall; got: %d", len(root.Subcalls))
+	}
+	if sub := root.Subcalls[0]; sub.Receipt.ExitCode != 16 || sub.Error != "forbidden" || sub.Message.To != mustIDAddress(4) {
+		t.Fatalf("unexpected subcall: %+v", sub)
+	}
+}
+
+func TestTracesCircularSerdeV2(t *testing.T) {
+	traces := []ExecutionTrace{{
+		Message: &TraceMessage{
+			From:   mustIDAddress(101),
+			To:     mustIDAddress(100),
+			Value:  big.NewInt(100),
+			Method: 2,
+		},
+		Receipt:    &Receipt{ExitCode: 0, GasUsed: 500},
+		GasCharges: []GasCharge{{Name: "OnChainMessage", TotalGas: 300}},
+		Subcalls:   []ExecutionTrace{{Error: "boom"}},
+	}}
+
+	d, err := EncodeTraces(traces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := d.DecodeTraces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(traces, decoded) {
+		t.Fatalf("traces not equal; expected: %+v, got: %+v", traces, decoded)
+	}
+
+	d.Format = "unknown"
+	if _, err := d.DecodeTraces(); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestIndexQuery(t *testing.T) {
+	tv := testValidVector(t)
+	tv.Meta = &Metadata{ID: "transfer-ok", Tags: []string{"transfer"}}
+	tv.Selector = Selector{SelectorChaosActor: "true"}
+
+	msg, err := NewIndexEntry("transfer/a.json", &tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, _ := tv.ContentHash(); msg.Messages != 2 || msg.ContentHash != hash || msg.ID != "transfer-ok" {
+		t.Fatalf("unexpected index entry: %+v", msg)
+	}
+
+	tv.Class, tv.Selector, tv.Hints = ClassTipset, nil, []string{HintIncorrect}
+	tv.Meta = &Metadata{ID: "paych-settle", Tags: []string{"paych"}}
+	tv.ApplyMessages = nil
+	miner := mustIDAddress(1000)
+	tv.ApplyTipsets = []Tipset{{Blocks: []Block{
+		{MinerAddr: miner, Messages: []Base64EncodedBytes{testMessage()}},
+		{MinerAddr: miner, Messages: []Base64EncodedBytes{testMessage()}},
+		{MinerAddr: miner, BLSMessages: []Base64EncodedBytes{testMessage()}, SECPMessages: []Base64EncodedBytes{testSignedMessage(SigTypeSecp256k1)}},
+	}}}
+	ts, err := NewIndexEntry("paych/b.json", &tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Messages != 4 {
+		t.Fatalf("expected 4 messages; got: %d", ts.Messages)
+	}
+
+	idx := Index{Vectors: []IndexEntry{msg, ts}}
+	nv := func(v uint) *uint { return &v }
+
+	cases := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{"all", Query{}, []string{"transfer-ok", "paych-settle"}},
+		{"class", Query{Class: ClassTipset}, []string{"paych-settle"}},
+		{"id", Query{ID: regexp.MustCompile("^transfer-")}, []string{"transfer-ok"}},
+		{"selector", Query{Selector: Selector{SelectorChaosActor: "true"}}, []string{"transfer-ok"}},
+		{"tag", Query{Tags: []string{"paych"}}, []string{"paych-settle"}},
+		{"hint", Query{Hints: []string{HintIncorrect}}, []string{"paych-settle"}},
+		{"exclude hint", Query{ExcludeHints: []string{HintIncorrect}}, []string{"transfer-ok"}},
+		{"network version", Query{NetworkVersion: nv(1)}, []string{"transfer-ok", "paych-settle"}},
+		{"variant and network version", Query{Variant: "genesis", NetworkVersion: nv(1)}, nil},
+		{"combined", Query{Class: ClassTipset, Selector: Selector{SelectorChaosActor: "true"}, NetworkVersion: nv(0)}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var actual []string
+			for _, e := range idx.Query(c.query) {
+				actual = append(actual, e.ID)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Fatalf("expected %v; got: %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestVectorFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vectors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{"b/2.json", "b/1.json", "a.json", IndexFile, "MANIFEST", "c/notes.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := VectorFiles(dir, filepath.Join(dir, "a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rel []string
+	for _, f := range files {
+		r, err := filepath.Rel(dir, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	if expected := []string{"a.json", "b/1.json", "b/2.json", "a.json"}; !reflect.DeepEqual(rel, expected) {
+		t.Fatalf("expected %v, got %v", expected, rel)
+	}
+
+	if _, err := VectorFiles(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected an error walking a missing path")
+	}
+}
@@ -0,0 +1,229 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+// ValidationError is a violation of a validation rule by a test vector.
+type ValidationError struct {
+	// Field is the path to the offending field, using the JSON field names,
+	// e.g. "apply_tipsets[2].epoch_offset".
+	Field string
+	// Reason describes the violation.
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationErrors is the set of violations found in a test vector. It is the
+// concrete type of the error returned by TestVector.Validate.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d validation errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
+func (e *ValidationErrors) add(field string, format string, args ...interface{}) {
+	*e = append(*e, ValidationError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Validate applies validation rules that cannot be enforced through JSON
+// Schema, checking that the vector is internally consistent. It returns nil
+// if the vector is valid, or ValidationErrors enumerating every violation.
+//
+// The rules are:
+//
+//   - there's at least one variant, and variant IDs are unique.
+//   - message-class vectors have a receipt per message, and every
+//     apply_message_failures index refers to a message with a nil receipt.
+//   - tipset-class vectors have a receipts root per tipset, and tipset epoch
+//     offsets are strictly increasing.
+//   - blockseq-class block epoch offsets are non-decreasing.
+//   - the CAR decompresses, and contains the pre and post state roots.
+//   - every message, tipset block message, and block message decodes as a
+//     message.
+//   - the content hash, if present in the metadata, matches the vector.
+func (tv TestVector) Validate() error {
+	var errs ValidationErrors
+
+	if tv.Pre == nil {
+		errs.add("preconditions", "missing")
+	} else {
+		tv.validateVariants(&errs)
+	}
+
+	if tv.Post == nil {
+		errs.add("postconditions", "missing")
+	} else {
+		switch tv.Class {
+		case ClassMessage:
+			tv.validateMessageClass(&errs)
+		case ClassTipset:
+			tv.validateTipsetClass(&errs)
+		}
+	}
+
+	if tv.Class == ClassBlockSeq {
+		for i := 1; i < len(tv.ApplyBlocks); i++ {
+			if prev, curr := tv.ApplyBlocks[i-1].EpochOffset, tv.ApplyBlocks[i].EpochOffset; curr < prev {
+				errs.add(fmt.Sprintf("apply_blocks[%d].epoch_offset", i), "must not be lower than previous block's (%d < %d)", curr, prev)
+			}
+		}
+	}
+
+	tv.validateCAR(&errs)
+	tv.validateMessages(&errs)
+
+	if tv.Meta != nil && tv.Meta.ContentHash != "" {
+		switch hash, err := tv.ContentHash(); {
+		case err != nil:
+			errs.add("_meta.content_hash", "failed to compute content hash: %s", err)
+		case hash != tv.Meta.ContentHash:
+			errs.add("_meta.content_hash", "%s does not match the vector's content hash %s", tv.Meta.ContentHash, hash)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (tv TestVector) validateVariants(errs *ValidationErrors) {
+	if len(tv.Pre.Variants) == 0 {
+		errs.add("preconditions.variants", "must not be empty")
+	}
+	seen := make(map[string]int, len(tv.Pre.Variants))
+	for i, v := range tv.Pre.Variants {
+		if j, ok := seen[v.ID]; ok {
+			errs.add(fmt.Sprintf("preconditions.variants[%d].id", i), "duplicate variant %q; first seen at index %d", v.ID, j)
+			continue
+		}
+		seen[v.ID] = i
+	}
+}
+
+func (tv TestVector) validateMessageClass(errs *ValidationErrors) {
+	if len(tv.Post.Receipts) != len(tv.ApplyMessages) {
+		errs.add("postconditions.receipts", "length of postcondition receipts (%d) must match length of messages to apply (%d)", len(tv.Post.Receipts), len(tv.ApplyMessages))
+	}
+
+	failures := make(map[int]struct{}, len(tv.Post.ApplyMessageFailures))
+	for i, idx := range tv.Post.ApplyMessageFailures {
+		field := fmt.Sprintf("postconditions.apply_message_failures[%d]", i)
+		if _, ok := failures[idx]; ok {
+			errs.add(field, "duplicate index %d", idx)
+			continue
+		}
+		failures[idx] = struct{}{}
+
+		switch {
+		case idx < 0 || idx >= len(tv.ApplyMessages):
+			errs.add(field, "index %d out of range; vector has %d messages", idx, len(tv.ApplyMessages))
+		case idx < len(tv.Post.Receipts) && tv.Post.Receipts[idx] != nil:
+			errs.add(field, "message %d is expected to fail, but has a receipt", idx)
+		}
+	}
+
+	for i, r := range tv.Post.Receipts {
+		if _, ok := failures[i]; r == nil && !ok {
+			errs.add(fmt.Sprintf("postconditions.receipts[%d]", i), "nil receipt for a message not listed in apply_message_failures")
+		}
+	}
+}
+
+func (tv TestVector) validateTipsetClass(errs *ValidationErrors) {
+	if len(tv.Post.ReceiptsRoots) != len(tv.ApplyTipsets) {
+		errs.add("postconditions.receipts_roots", "length of postcondition receipts roots (%d) must match length of tipsets to apply (%d)", len(tv.Post.ReceiptsRoots), len(tv.ApplyTipsets))
+	}
+	for i := 1; i < len(tv.ApplyTipsets); i++ {
+		if prev, curr := tv.ApplyTipsets[i-1].EpochOffset, tv.ApplyTipsets[i].EpochOffset; curr <= prev {
+			errs.add(fmt.Sprintf("apply_tipsets[%d].epoch_offset", i), "must be strictly greater than previous tipset's (%d <= %d)", curr, prev)
+		}
+	}
+}
+
+func (tv TestVector) validateCAR(errs *ValidationErrors) {
+	cids, err := carCIDs(tv.CAR)
+	if err != nil {
+		errs.add("car", "%s", err)
+		return
+	}
+
+	roots := []struct {
+		field string
+		st    *StateTree
+	}{
+		{"preconditions.state_tree.root_cid", nil},
+		{"postconditions.state_tree.root_cid", nil},
+	}
+	if tv.Pre != nil {
+		roots[0].st = tv.Pre.StateTree
+	}
+	if tv.Post != nil {
+		roots[1].st = tv.Post.StateTree
+	}
+
+	for _, r := range roots {
+		switch {
+		case r.st == nil || !r.st.RootCID.Defined():
+			errs.add(r.field, "missing")
+		case !containsCID(cids, r.st.RootCID):
+			errs.add(r.field, "root %s not present in the CAR", r.st.RootCID)
+		}
+	}
+}
+
+func containsCID(set map[cid.Cid]struct{}, c cid.Cid) bool {
+	_, ok := set[c]
+	return ok
+}
+
+func (tv TestVector) validateMessages(errs *ValidationErrors) {
+	check := func(field string, b []byte, signed bool) {
+		decode := decodeMessage
+		if signed {
+			decode = decodeSignedMessage
+		}
+		if err := decode(b); err != nil {
+			errs.add(field, "failed to decode message: %s", err)
+		}
+	}
+	for i, m := range tv.ApplyMessages {
+		check(fmt.Sprintf("apply_messages[%d].bytes", i), m.Bytes, m.Signed)
+	}
+	for i, ts := range tv.ApplyTipsets {
+		for j, blk := range ts.Blocks {
+			prefix := fmt.Sprintf("apply_tipsets[%d].blocks[%d]", i, j)
+			if blk.Signed() && len(blk.Messages) > 0 {
+				errs.add(prefix+".messages", "must be empty in a block with signed messages")
+			}
+			for k, m := range blk.Messages {
+				check(fmt.Sprintf("%s.messages[%d]", prefix, k), m, false)
+			}
+			for k, m := range blk.BLSMessages {
+				check(fmt.Sprintf("%s.bls_messages[%d]", prefix, k), m, false)
+			}
+			for k, m := range blk.SECPMessages {
+				check(fmt.Sprintf("%s.secp_messages[%d]", prefix, k), m, true)
+			}
+		}
+	}
+	for i, blk := range tv.ApplyBlocks {
+		for j, m := range blk.BLSMessages {
+			check(fmt.Sprintf("apply_blocks[%d].bls_messages[%d]", i, j), m, false)
+		}
+		for j, m := range blk.SECPMessages {
+			check(fmt.Sprintf("apply_blocks[%d].secp_messages[%d]", i, j), m, true)
+		}
+	}
+}